@@ -0,0 +1,144 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+// Command vstack-import writes Terraform configuration and import blocks for the existing VMs of a
+// vStack cluster. Review the generated files, then run "terraform apply" to import the VMs and their NICs.
+//
+// Usage:
+//
+//	vstack-import -host https://vstack.example.com -vdc 1 [-name-regex REGEX] [-out DIR] [-module-per-vm]
+//
+// The credentials are read from -username and -password or from the VSTACK_USERNAME and VSTACK_PASSWORD
+// environment variables, the host from VSTACK_HOST if -host is not set.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"terraform-provider-vstack/internal/hclgen"
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// config holds the command-line options.
+type config struct {
+	host               string
+	username           string
+	password           string
+	vdcID              int64
+	nameRegex          string
+	out                string
+	modulePerVM        bool
+	insecureSkipVerify bool
+	caCertFile         string
+}
+
+func main() {
+	cfg, err := parseFlags(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "vstack-import:", err)
+		os.Exit(2)
+	}
+
+	if err := run(context.Background(), cfg, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "vstack-import:", err)
+		os.Exit(1)
+	}
+}
+
+// parseFlags parses the command-line arguments, falling back to the provider's environment variables.
+func parseFlags(args []string) (config, error) {
+	var cfg config
+	fs := flag.NewFlagSet("vstack-import", flag.ContinueOnError)
+	fs.StringVar(&cfg.host, "host", os.Getenv("VSTACK_HOST"), "vStack API host (default $VSTACK_HOST)")
+	fs.StringVar(&cfg.username, "username", os.Getenv("VSTACK_USERNAME"), "vStack API username (default $VSTACK_USERNAME)")
+	fs.StringVar(&cfg.password, "password", os.Getenv("VSTACK_PASSWORD"), "vStack API password (default $VSTACK_PASSWORD)")
+	fs.Int64Var(&cfg.vdcID, "vdc", 0, "only import VMs of this VDC (default all VDCs)")
+	fs.StringVar(&cfg.nameRegex, "name-regex", "", "only import VMs whose name matches this regular expression")
+	fs.StringVar(&cfg.out, "out", "", "directory to write the configuration to (default stdout)")
+	fs.BoolVar(&cfg.modulePerVM, "module-per-vm", false, "write every VM to its own module under modules/; requires -out")
+	fs.BoolVar(&cfg.insecureSkipVerify, "insecure-skip-verify", false, "disable verification of the API certificate")
+	fs.StringVar(&cfg.caCertFile, "ca-cert-file", os.Getenv("VSTACK_CA_CERT_FILE"), "PEM file with additional trusted CA certificates (default $VSTACK_CA_CERT_FILE)")
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	switch {
+	case fs.NArg() > 0:
+		return config{}, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	case cfg.host == "":
+		return config{}, errors.New("-host or VSTACK_HOST must be set")
+	case cfg.username == "" || cfg.password == "":
+		return config{}, errors.New("-username and -password or VSTACK_USERNAME and VSTACK_PASSWORD must be set")
+	case cfg.modulePerVM && cfg.out == "":
+		return config{}, errors.New("-module-per-vm writes several files and requires -out")
+	}
+	return cfg, nil
+}
+
+// run lists the VMs selected by cfg and writes their configuration to cfg.out, or to stdout if it is empty.
+func run(ctx context.Context, cfg config, stdout io.Writer) error {
+	// 1. Build the filter.
+	filter := helper.VMFilter{VdcID: cfg.vdcID}
+	if cfg.nameRegex != "" {
+		nameRegex, err := regexp.Compile(cfg.nameRegex)
+		if err != nil {
+			return fmt.Errorf("invalid -name-regex: %w", err)
+		}
+		filter.NameRegex = nameRegex
+	}
+
+	// 2. Connect to vStack.
+	httpClient, err := vstack_api.NewHTTPClient(vstack_api.TransportConfig{
+		CACertFile:         cfg.caCertFile,
+		InsecureSkipVerify: cfg.insecureSkipVerify,
+	})
+	if err != nil {
+		return err
+	}
+	client := vstack_api.NewClient(strings.TrimRight(cfg.host, "/"), httpClient)
+	if err := client.Auth(ctx, vstack_api.AuthParams{Username: cfg.username, Password: cfg.password}); err != nil {
+		return err
+	}
+
+	// 3. Retrieve the VMs and generate their configuration.
+	vms, err := helper.ListVMs(ctx, client, filter)
+	if err != nil {
+		return err
+	}
+	if len(vms) == 0 {
+		return errors.New("no VMs match the given filters")
+	}
+	files, err := hclgen.Generate(vms, hclgen.Options{ModulePerVM: cfg.modulePerVM})
+	if err != nil {
+		return err
+	}
+
+	// 4. Write the files.
+	if cfg.out == "" {
+		_, err := stdout.Write(files[0].Content)
+		return err
+	}
+	for _, file := range files {
+		name := filepath.Join(cfg.out, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(name, file.Content, 0o644); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(stdout, "Wrote %d VMs to %s\n", len(vms), cfg.out)
+	return nil
+}
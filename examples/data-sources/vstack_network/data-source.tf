@@ -0,0 +1,5 @@
+# Look up a network by name within a VDC
+data "vstack_network" "backend" {
+  name   = "backend"
+  vdc_id = 1234
+}
@@ -1,4 +1,9 @@
 #Get vm params
 data "vstack_vm_get" "example" {
   id = 1234
-}
\ No newline at end of file
+}
+#Get vm params by name
+data "vstack_vm_get" "by_name" {
+  name   = "web-1"
+  vdc_id = 1234
+}
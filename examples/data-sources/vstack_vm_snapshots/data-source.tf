@@ -0,0 +1,8 @@
+# List the snapshots of a VM
+data "vstack_vm_snapshots" "example" {
+  vm_id = 1234
+}
+
+output "snapshot_names" {
+  value = [for s in data.vstack_vm_snapshots.example.snapshots : s.name]
+}
@@ -0,0 +1,10 @@
+# List the started web servers of a VDC
+data "vstack_vms" "web" {
+  vdc_id      = 1234
+  name_regex  = "^web-"
+  oper_status = 3
+}
+
+output "web_vm_ids" {
+  value = data.vstack_vms.web.ids
+}
@@ -0,0 +1,4 @@
+# Network instance can be imported by specifying its identifier
+# id - is ID of the network that you want to import
+
+terraform import vstack_network.backend 5678
@@ -0,0 +1,14 @@
+# Manage VDC network
+resource "vstack_network" "backend" {
+  name    = "backend"
+  vdc_id  = 1234
+  type    = "vxlan"
+  cidr    = "192.168.10.0/24"
+  gateway = "192.168.10.1"
+
+  dhcp = {
+    range_start = "192.168.10.100"
+    range_end   = "192.168.10.200"
+    dns_servers = ["192.168.10.1"]
+  }
+}
@@ -12,7 +12,11 @@ resource "vstack_vm" "example" {
   vdc_id        = 1234
   pool_selector = "12345678911234567891"
 
-  action = "start"
+  action           = "start"
+  shutdown_timeout = "5m"
+
+  # Reboot the VM whenever the configuration it runs with changes.
+  reboot_trigger = sha1(file("${path.module}/app.conf"))
 
   disks = [
     {
@@ -44,4 +48,29 @@ resource "vstack_vm" "example" {
 
     run_cmds = ["systemctl restart ntpd"]
   }
-}
\ No newline at end of file
+}
+
+# Clone a VM from a snapshot of a golden image
+resource "vstack_vm" "from_golden" {
+  name   = "from-golden"
+  cpus   = 2
+  ram    = 4096
+  vdc_id = 1234
+
+  source = {
+    snapshot_id = "1234/12345678911234567891/vms/1234@golden"
+    linked      = true
+  }
+
+  guest = {
+    hostname = "from-golden"
+    users = {
+      root = {
+        ssh_authorized_keys = [
+          "ssh-rsa AAAAB3NzaC1..."
+        ]
+        password = "password"
+      }
+    }
+  }
+}
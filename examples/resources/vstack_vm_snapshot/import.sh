@@ -0,0 +1,4 @@
+# VM snapshot can be imported by specifying its identifier
+# id - is <vm_id>/<root_dataset>@<name>, as listed by the vstack_vm_snapshots data source
+
+terraform import vstack_vm_snapshot.before_upgrade 1234/14061357726568775332/vms/1234@before-upgrade
@@ -0,0 +1,8 @@
+# Snapshot the root dataset of a VM before a risky change, keeping it for a week
+resource "vstack_vm_snapshot" "before_upgrade" {
+  vm_id        = vstack_vm.example.id
+  root_dataset = vstack_vm.example.root_dataset
+  name         = "before-upgrade"
+  description  = "Taken before the database upgrade"
+  retention    = "168h"
+}
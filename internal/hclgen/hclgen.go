@@ -0,0 +1,249 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+// Package hclgen generates Terraform configuration and import blocks for existing vStack VMs.
+//
+// The VMs are mapped with helper.MapRespToState, so the generated vstack_vm blocks match what the
+// provider reads back after the import. Settings vStack does not return, like the guest
+// customization, are written as placeholders that Terraform is told to ignore.
+package hclgen
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// ProviderSource is the registry address of the provider written to the required_providers of generated modules.
+const ProviderSource = "IvanBrykalov/vstack"
+
+// Options controls the layout of the generated configuration.
+type Options struct {
+	// ModulePerVM writes every VM with its NICs to its own module under "modules/<name>" and
+	// the module calls and import blocks to "main.tf". Otherwise everything is written to "main.tf".
+	ModulePerVM bool
+}
+
+// File is a generated configuration file.
+type File struct {
+	Path    string // Slash-separated path relative to the output directory.
+	Content []byte
+}
+
+// vmConfig is a VM prepared for generation.
+type vmConfig struct {
+	label string // Unique Terraform name of the VM.
+	model models.VMResourceModel
+	ports []vstack_api.NetworkPort
+}
+
+// Generate returns the configuration and import blocks adopting the VMs and their NICs.
+//
+// Parameters:
+// - vms: The "vm-get" results of the VMs, e.g. from helper.ListVMs.
+// - opts: The layout of the generated configuration.
+//
+// Returns:
+// - The generated files, "main.tf" first.
+// - An error if a VM cannot be mapped to the vstack_vm resource.
+func Generate(vms []vstack_api.VmGetResult, opts Options) ([]File, error) {
+	configs, err := prepare(vms)
+	if err != nil {
+		return nil, err
+	}
+
+	root := hclwrite.NewEmptyFile()
+	var modules []File
+
+	for i, vm := range configs {
+		if i > 0 {
+			root.Body().AppendNewline()
+		}
+
+		if !opts.ModulePerVM {
+			writeVM(root.Body(), vm, vm.label, nicLabel)
+			root.Body().AppendNewline()
+			writeImports(root.Body(), vm, "vstack_vm."+vm.label, func(slot int64) string {
+				return "vstack_nic." + nicLabel(vm.label, slot)
+			})
+			continue
+		}
+
+		// The module of the VM names its resources independently of the VM.
+		module := hclwrite.NewEmptyFile()
+		writeRequiredProviders(module.Body())
+		module.Body().AppendNewline()
+		writeVM(module.Body(), vm, "this", func(_ string, slot int64) string { return fmt.Sprintf("nic%d", slot) })
+		modules = append(modules, File{Path: path.Join("modules", vm.label, "main.tf"), Content: hclwrite.Format(module.Bytes())})
+
+		call := root.Body().AppendNewBlock("module", []string{vm.label})
+		call.Body().SetAttributeValue("source", cty.StringVal("./"+path.Join("modules", vm.label)))
+		root.Body().AppendNewline()
+		writeImports(root.Body(), vm, "module."+vm.label+".vstack_vm.this", func(slot int64) string {
+			return fmt.Sprintf("module.%s.vstack_nic.nic%d", vm.label, slot)
+		})
+	}
+
+	return append([]File{{Path: "main.tf", Content: hclwrite.Format(root.Bytes())}}, modules...), nil
+}
+
+// prepare maps the VMs to the resource model and assigns them unique Terraform names.
+func prepare(vms []vstack_api.VmGetResult) ([]vmConfig, error) {
+	configs := make([]vmConfig, 0, len(vms))
+	used := map[string]bool{}
+	for _, vm := range vms {
+		model, err := helper.MapRespToState(vm, models.VMResourceModel{})
+		if err != nil {
+			return nil, fmt.Errorf("Generate: VM %d: %w", vm.Data.ID, err)
+		}
+
+		label := Label(vm.Data.Name)
+		if used[label] {
+			label = fmt.Sprintf("%s_%d", label, vm.Data.ID)
+		}
+		used[label] = true
+
+		ports := append([]vstack_api.NetworkPort{}, vm.Data.NetworkPorts...)
+		sort.Slice(ports, func(i, j int) bool { return ports[i].Slot < ports[j].Slot })
+		configs = append(configs, vmConfig{label: label, model: model, ports: ports})
+	}
+	return configs, nil
+}
+
+// invalidLabelChars matches the characters not allowed in generated Terraform names.
+var invalidLabelChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// Label converts a VM name to a Terraform name: lower case letters, digits and underscores,
+// starting with a letter or an underscore.
+func Label(name string) string {
+	label := strings.Trim(invalidLabelChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if label == "" {
+		return "vm"
+	}
+	if label[0] >= '0' && label[0] <= '9' {
+		label = "vm_" + label
+	}
+	return label
+}
+
+// nicLabel returns the Terraform name of a NIC of a VM in the single-file layout.
+func nicLabel(vmLabel string, slot int64) string {
+	return fmt.Sprintf("%s_nic%d", vmLabel, slot)
+}
+
+// writeRequiredProviders appends the terraform block a module needs to use the provider.
+func writeRequiredProviders(body *hclwrite.Body) {
+	required := body.AppendNewBlock("terraform", nil).Body().AppendNewBlock("required_providers", nil)
+	required.Body().SetAttributeValue("vstack", cty.ObjectVal(map[string]cty.Value{
+		"source": cty.StringVal(ProviderSource),
+	}))
+}
+
+// writeVM appends the vstack_vm resource and the vstack_nic resources of the VM.
+func writeVM(body *hclwrite.Body, vm vmConfig, label string, nicName func(vmLabel string, slot int64) string) {
+	m := vm.model
+
+	resource := body.AppendNewBlock("resource", []string{"vstack_vm", label}).Body()
+	resource.SetAttributeValue("name", cty.StringVal(m.Name.ValueString()))
+	if !m.Description.IsNull() {
+		resource.SetAttributeValue("description", cty.StringVal(m.Description.ValueString()))
+	}
+	resource.SetAttributeValue("cpus", cty.NumberIntVal(m.CPUs.ValueInt64()))
+	resource.SetAttributeValue("ram", cty.NumberIntVal(m.RAM.ValueInt64()))
+	resource.SetAttributeValue("cpu_priority", cty.NumberIntVal(m.CPUPriority.ValueInt64()))
+	resource.SetAttributeValue("boot_media", cty.NumberIntVal(m.BootMedia.ValueInt64()))
+	resource.SetAttributeValue("vcpu_class", cty.NumberIntVal(m.VcpuClass.ValueInt64()))
+	resource.SetAttributeValue("os_profile", cty.StringVal(m.OsProfile.ValueString()))
+	resource.SetAttributeValue("vdc_id", cty.NumberIntVal(m.VdcID.ValueInt64()))
+	resource.SetAttributeValue("pool_selector", cty.StringVal(m.PoolSelector.ValueString()))
+	if action := m.Action.ValueString(); action != "" {
+		resource.SetAttributeValue("action", cty.StringVal(action))
+	}
+
+	disks := make([]cty.Value, 0, len(m.Disks))
+	for _, disk := range m.Disks {
+		attrs := map[string]cty.Value{
+			"size": cty.NumberIntVal(disk.Size.ValueInt64()),
+			"slot": cty.NumberIntVal(disk.Slot.ValueInt64()),
+		}
+		if label := disk.Label.ValueString(); label != "" {
+			attrs["label"] = cty.StringVal(label)
+		}
+		if !disk.IopsLimit.IsNull() {
+			attrs["iops_limit"] = cty.NumberIntVal(disk.IopsLimit.ValueInt64())
+		}
+		if !disk.MbpsLimit.IsNull() {
+			attrs["mbps_limit"] = cty.NumberIntVal(disk.MbpsLimit.ValueInt64())
+		}
+		disks = append(disks, cty.ObjectVal(attrs))
+	}
+	if len(disks) == 0 {
+		resource.SetAttributeValue("disks", cty.EmptyTupleVal)
+	} else {
+		resource.SetAttributeValue("disks", cty.TupleVal(disks))
+	}
+
+	// vStack does not return the guest customization, and changing it replaces the VM.
+	resource.AppendNewline()
+	appendComment(resource, "Guest customization is applied when a VM is created and cannot be read back from vStack.")
+	appendComment(resource, "These placeholders are ignored; replace them when recreating the VM.")
+	resource.SetAttributeValue("guest", cty.ObjectVal(map[string]cty.Value{
+		"hostname": cty.StringVal(m.Name.ValueString()),
+		"users":    cty.EmptyObjectVal,
+	}))
+	resource.AppendNewline()
+	lifecycle := resource.AppendNewBlock("lifecycle", nil).Body()
+	lifecycle.SetAttributeRaw("ignore_changes", hclwrite.TokensForTuple([]hclwrite.Tokens{hclwrite.TokensForIdentifier("guest")}))
+
+	for _, port := range vm.ports {
+		body.AppendNewline()
+		nic := body.AppendNewBlock("resource", []string{"vstack_nic", nicName(vm.label, port.Slot)}).Body()
+		nic.SetAttributeTraversal("vm_id", hcl.Traversal{
+			hcl.TraverseRoot{Name: "vstack_vm"},
+			hcl.TraverseAttr{Name: label},
+			hcl.TraverseAttr{Name: "id"},
+		})
+		nic.SetAttributeValue("network_id", cty.NumberIntVal(port.NetworkID))
+		nic.SetAttributeValue("slot", cty.NumberIntVal(port.Slot))
+		if port.Address != "" {
+			nic.SetAttributeValue("address", cty.StringVal(port.Address))
+		}
+		nic.SetAttributeValue("ip_guard", cty.NumberIntVal(port.IPGuard))
+		if port.RatelimitMBits != nil {
+			nic.SetAttributeValue("ratelimit_mbits", cty.NumberIntVal(*port.RatelimitMBits))
+		}
+	}
+}
+
+// writeImports appends the import blocks of the VM and its NICs.
+func writeImports(body *hclwrite.Body, vm vmConfig, vmAddress string, nicAddress func(slot int64) string) {
+	vmID := vm.model.ID.ValueInt64()
+	writeImport(body, vmAddress, fmt.Sprintf("%d", vmID))
+	for _, port := range vm.ports {
+		body.AppendNewline()
+		writeImport(body, nicAddress(port.Slot), fmt.Sprintf("%d/%d", vmID, port.PortID))
+	}
+}
+
+// writeImport appends an import block of the resource at address.
+func writeImport(body *hclwrite.Body, address, id string) {
+	block := body.AppendNewBlock("import", nil).Body()
+	block.SetAttributeRaw("to", hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(address)}})
+	block.SetAttributeValue("id", cty.StringVal(id))
+}
+
+// appendComment appends a line comment to the body.
+func appendComment(body *hclwrite.Body, text string) {
+	body.AppendUnstructuredTokens(hclwrite.Tokens{{Type: hclsyntax.TokenComment, Bytes: []byte("# " + text + "\n")}})
+}
@@ -0,0 +1,152 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package hclgen_test
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+
+	"terraform-provider-vstack/internal/hclgen"
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/vstack_api"
+	"terraform-provider-vstack/internal/vstacktest"
+)
+
+// listFakeVMs creates two VMs named "web-1", each with a disk and a NIC, on the fake server and returns their details.
+func listFakeVMs(t *testing.T) []vstack_api.VmGetResult {
+	t.Helper()
+	ctx := context.Background()
+
+	server := vstacktest.NewServer()
+	t.Cleanup(server.Close)
+	client := vstack_api.NewClient(server.URL, server.Client())
+	client.SetRetryPolicy(vstack_api.RetryPolicy{})
+	if err := client.Auth(ctx, vstack_api.AuthParams{Username: server.Username, Password: server.Password}); err != nil {
+		t.Fatalf("Auth: %v", err)
+	}
+
+	for _, name := range []string{"web-1", "web-1"} {
+		vm, err := client.VmCreate(ctx, vstack_api.VmCreateParams{
+			Name:      name,
+			CPUs:      2,
+			RAM:       2 << 30,
+			OsProfile: "4001",
+			VdcID:     vstacktest.DefaultVdcID,
+			Disks:     []vstack_api.DiskParams{{Size: 10 << 30, Slot: 1, Label: "root"}},
+		})
+		if err != nil {
+			t.Fatalf("VmCreate: %v", err)
+		}
+		if _, err := client.VmsAddNic(ctx, vstack_api.VmsAddNicParams{ID: vm.Data.ID, NetworkID: vstacktest.DefaultNetworkID, Slot: 1}); err != nil {
+			t.Fatalf("VmsAddNic: %v", err)
+		}
+	}
+
+	vms, err := helper.ListVMs(ctx, client, helper.VMFilter{})
+	if err != nil {
+		t.Fatalf("ListVMs: %v", err)
+	}
+	return vms
+}
+
+// parse fails the test if the file is not valid HCL and returns its content with the alignment
+// of the equals signs removed.
+func parse(t *testing.T, file hclgen.File) string {
+	t.Helper()
+	if _, diags := hclparse.NewParser().ParseHCL(file.Content, file.Path); diags.HasErrors() {
+		t.Fatalf("%s is not valid HCL: %s\n%s", file.Path, diags.Error(), file.Content)
+	}
+	return alignment.ReplaceAllString(string(file.Content), " = ")
+}
+
+var alignment = regexp.MustCompile(` += `)
+
+func TestGenerateSingleFile(t *testing.T) {
+	vms := listFakeVMs(t)
+
+	files, err := hclgen.Generate(vms, hclgen.Options{})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "main.tf" {
+		t.Fatalf("files = %v, want only main.tf", files)
+	}
+	content := parse(t, files[0])
+	secondLabel := hclgen.Label("web-1") + "_" + itoa(vms[1].Data.ID)
+	for _, want := range []string{
+		`resource "vstack_vm" "web_1" {`,
+		`resource "vstack_vm" "` + secondLabel + `" {`,
+		`resource "vstack_nic" "web_1_nic1" {`,
+		"vm_id = vstack_vm.web_1.id",
+		"to = vstack_vm.web_1",
+		`id = "` + itoa(vms[0].Data.ID) + `"`,
+		`id = "` + itoa(vms[0].Data.ID) + "/" + itoa(vms[0].Data.NetworkPorts[0].PortID) + `"`,
+		"ignore_changes = [guest]",
+		`label = "root"`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("main.tf does not contain %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestGenerateModulePerVM(t *testing.T) {
+	vms := listFakeVMs(t)
+
+	files, err := hclgen.Generate(vms, hclgen.Options{ModulePerVM: true})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(files) != 1+len(vms) {
+		t.Fatalf("got %d files, want main.tf and one module per VM", len(files))
+	}
+	root := parse(t, files[0])
+	for _, want := range []string{
+		`module "web_1" {`,
+		`source = "./modules/web_1"`,
+		"to = module.web_1.vstack_vm.this",
+		"to = module.web_1.vstack_nic.nic1",
+	} {
+		if !strings.Contains(root, want) {
+			t.Errorf("main.tf does not contain %q:\n%s", want, root)
+		}
+	}
+
+	if files[1].Path != "modules/web_1/main.tf" {
+		t.Fatalf("files[1].Path = %q, want modules/web_1/main.tf", files[1].Path)
+	}
+	module := parse(t, files[1])
+	for _, want := range []string{
+		`source = "` + hclgen.ProviderSource + `"`,
+		`resource "vstack_vm" "this" {`,
+		`resource "vstack_nic" "nic1" {`,
+		"vm_id = vstack_vm.this.id",
+	} {
+		if !strings.Contains(module, want) {
+			t.Errorf("module does not contain %q:\n%s", want, module)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	for name, want := range map[string]string{
+		"web-1":      "web_1",
+		"DB.Primary": "db_primary",
+		"1c-server":  "vm_1c_server",
+		"--":         "vm",
+	} {
+		if got := hclgen.Label(name); got != want {
+			t.Errorf("Label(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func itoa(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
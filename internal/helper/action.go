@@ -4,8 +4,10 @@
 package helper
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
+	"time"
 
 	"terraform-provider-vstack/internal/vstack_api"
 )
@@ -28,33 +30,84 @@ var Action = map[string]ActionVM{
 		OperStatus: Status.Offline, // Assumes Status.Offline is a predefined constant
 		Method:     "vms-stop",
 	},
+	"shutdown": {
+		OperStatus: Status.Offline,
+		Method:     "vms-shutdown", // ACPI shutdown, completed only if the guest cooperates
+	},
+	"poweroff": {
+		OperStatus: Status.Offline,
+		Method:     "vms-stop",
+	},
+	"suspend": {
+		OperStatus: Status.Suspended,
+		Method:     "vms-suspend",
+	},
+	"resume": {
+		OperStatus: Status.Started,
+		Method:     "vms-resume",
+	},
 }
 
 // Execute performs the specified action on the VM identified by vmID.
-// It constructs the appropriate JSON-RPC request and invokes the corresponding API method.
+// It invokes the corresponding API method through the vStack API client.
 //
 // Parameters:
+// - ctx: The context of the operation; cancelling it aborts the API call.
 // - vmID: The unique identifier of the VM on which the action is to be performed.
-// - client: An HTTP client used to make API requests.
-// - authCookie: The authentication cookie required for API access.
-// - baseURL: The base URL of the API endpoint.
+// - client: The vStack API client used to make API requests.
 //
 // Returns:
 // - An error if the API call fails or if the action execution encounters issues.
-func (a ActionVM) Execute(vmID int64, client *http.Client, authCookie, baseURL string) error {
-	// Construct the JSON-RPC request payload using the specified method and VM ID.
-	requestPayload := BuildJSONRPCRequest(a.Method, map[string]interface{}{
-		"id": vmID,
-	})
-
+func (a ActionVM) Execute(ctx context.Context, vmID int64, client *vstack_api.Client) error {
 	// Execute the API call to perform the action (e.g., restart or stop the VM).
-	// The VmsStartStop function is assumed to handle the specific API interaction.
-	_, err := vstack_api.VmsStartStop(requestPayload, authCookie, baseURL, client)
+	// The VmsStartStop method is assumed to handle the specific API interaction.
+	_, err := client.VmsStartStop(ctx, a.Method, vstack_api.VmsStartStopParams{ID: vmID})
 	if err != nil {
 		// Wrap and return the error with additional context for easier debugging.
-		return fmt.Errorf("SetNicRatelimit: error executing action '%s' for VM ID %d: %w", a.Method, vmID, err)
+		return fmt.Errorf("Execute: error executing action '%s' for VM ID %d: %w", a.Method, vmID, err)
 	}
 
 	// Action executed successfully.
 	return nil
 }
+
+// DefaultShutdownGracePeriod is the default time a guest is given to shut down after an ACPI shutdown request.
+const DefaultShutdownGracePeriod = 2 * time.Minute
+
+// ShutdownVM shuts the VM down gracefully and powers it off if the guest does not shut down in time.
+//
+// Parameters:
+// - ctx: The context of the operation; cancelling it aborts the API calls and stops waiting.
+// - client: The vStack API client used to make API requests.
+// - vmID: The unique identifier of the VM.
+// - gracePeriod: The time to wait for the guest to shut down; DefaultShutdownGracePeriod is used if it is not positive.
+// - timeout: The maximum time to wait for the VM to go offline after it is powered off.
+//
+// Returns:
+// - forced: true if the VM had to be powered off.
+// - An error if the VM could not be stopped.
+func ShutdownVM(ctx context.Context, client *vstack_api.Client, vmID int64, gracePeriod, timeout time.Duration) (forced bool, err error) {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultShutdownGracePeriod
+	}
+
+	// 1. Ask the guest to shut down and give it the grace period to do so
+	shutdown := Action["shutdown"]
+	if err := shutdown.Execute(ctx, vmID, client); err != nil {
+		return false, fmt.Errorf("ShutdownVM: %w", err)
+	}
+	_, err = WaitForOperStatus(ctx, client, vmID, shutdown.OperStatus, gracePeriod)
+	if err == nil {
+		return false, nil
+	}
+	var timeoutErr *StatusTimeoutError
+	if !errors.As(err, &timeoutErr) || ctx.Err() != nil {
+		return false, fmt.Errorf("ShutdownVM: %w", err)
+	}
+
+	// 2. Power the VM off if the guest ignored the request
+	if err := PerformAction(ctx, client, vmID, "poweroff", timeout); err != nil {
+		return true, fmt.Errorf("ShutdownVM: guest did not shut down within %s: %w", gracePeriod, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+func TestPerformActionSuspendsAndResumesVM(t *testing.T) {
+	_, client, vmID := newFakeVM(t, "")
+	ctx := context.Background()
+
+	if err := helper.PerformAction(ctx, client, vmID, "suspend", 0); err == nil {
+		t.Error("expected suspending a VM that is not running to fail")
+	}
+	if err := helper.PerformAction(ctx, client, vmID, "start", 0); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := helper.PerformAction(ctx, client, vmID, "suspend", 0); err != nil {
+		t.Fatalf("suspend: %v", err)
+	}
+
+	// A suspended VM is mapped back to the "suspend" action.
+	resp, err := client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
+	if err != nil {
+		t.Fatalf("VmGet: %v", err)
+	}
+	state, err := helper.MapRespToState(resp, models.VMResourceModel{})
+	if err != nil {
+		t.Fatalf("MapRespToState: %v", err)
+	}
+	if resp.Data.OperStatus != helper.Status.Suspended || state.Action.ValueString() != "suspend" {
+		t.Errorf("expected a suspended VM, got oper status %d and action %q", resp.Data.OperStatus, state.Action.ValueString())
+	}
+	if _, err := client.VmRemove(ctx, vstack_api.VmRemoveParams{ID: vmID}); err == nil {
+		t.Error("expected removing a suspended VM to fail")
+	}
+
+	if err := helper.PerformAction(ctx, client, vmID, "resume", 0); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if running, err := helper.CheckIfVMIsRunning(ctx, client, vmID); err != nil || !running {
+		t.Errorf("expected the resumed VM to be running, got %v, %v", running, err)
+	}
+}
+
+func TestShutdownVMPowersOffIgnoringGuest(t *testing.T) {
+	interval := helper.StatusPollInterval
+	helper.StatusPollInterval = time.Millisecond
+	t.Cleanup(func() { helper.StatusPollInterval = interval })
+
+	server, client, vmID := newFakeVM(t, "")
+	ctx := context.Background()
+
+	// A cooperative guest shuts down within the grace period.
+	if err := helper.PerformAction(ctx, client, vmID, "start", 0); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	forced, err := helper.ShutdownVM(ctx, client, vmID, time.Second, time.Second)
+	if err != nil || forced {
+		t.Fatalf("expected a graceful shutdown, got forced=%v, err=%v", forced, err)
+	}
+
+	// A guest ignoring the request is powered off after the grace period.
+	server.IgnoreShutdown = true
+	if err := helper.PerformAction(ctx, client, vmID, "start", 0); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	forced, err = helper.ShutdownVM(ctx, client, vmID, 10*time.Millisecond, time.Second)
+	if err != nil || !forced {
+		t.Fatalf("expected a forced power off, got forced=%v, err=%v", forced, err)
+	}
+	if running, err := helper.CheckIfVMIsRunning(ctx, client, vmID); err != nil || running {
+		t.Errorf("expected the VM to be stopped, got %v, %v", running, err)
+	}
+}
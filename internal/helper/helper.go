@@ -4,49 +4,32 @@
 package helper
 
 import (
+	"context"
 	"fmt"
-	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"net/http"
 	"strings"
-	"sync"
+	"time"
+
 	"terraform-provider-vstack/internal/models"
 	"terraform-provider-vstack/internal/vstack_api"
 )
 
-// vmLocks stores a *sync.Mutex for each VM by its ID.
-var vmLocks sync.Map // map[int64]*sync.Mutex.
-
-func GetVMLock(vmID int64) (*sync.Mutex, error) {
-	actual, _ := vmLocks.LoadOrStore(vmID, &sync.Mutex{})
-	mutex, ok := actual.(*sync.Mutex)
-	if !ok {
-		return nil, fmt.Errorf("unexpected type in vmLocks for vmID %d", vmID)
-	}
-	return mutex, nil
-}
-
-// BuildJSONRPCRequest builds a JSON-RPC request with the specified method and parameters.
-func BuildJSONRPCRequest(method string, params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"id":      uuid.NewString(),
-		"jsonrpc": "2.0",
-		"method":  method,
-		"params":  params,
-	}
-}
-
-// PerformAction performs the specified action on the VM.
-// Returns an error if the operation fails or the action is unsupported.
-func PerformAction(client *http.Client, authCookie, baseURL string, vmID int64, actionName string) error {
+// PerformAction performs the specified action on the VM and waits until the VM reaches
+// the operational status of the action (e.g. Status.Started for "start").
+// Returns an error if the operation fails, the action is unsupported or the status is not reached within timeout.
+func PerformAction(ctx context.Context, client *vstack_api.Client, vmID int64, actionName string, timeout time.Duration) error {
 	actionName = strings.ToLower(actionName)
 	action, exists := Action[actionName]
 	if !exists {
 		return fmt.Errorf("unsupported action: %s", actionName)
 	}
 
-	if err := action.Execute(vmID, client, authCookie, baseURL); err != nil {
+	if err := action.Execute(ctx, vmID, client); err != nil {
+		return fmt.Errorf("failed to perform action '%s' on VM: %w", actionName, err)
+	}
+
+	if _, err := WaitForOperStatus(ctx, client, vmID, action.OperStatus, timeout); err != nil {
 		return fmt.Errorf("failed to perform action '%s' on VM: %w", actionName, err)
 	}
 
@@ -95,26 +78,60 @@ var Status = VmStatuses{
 	DeleteFailed:  17,
 }
 
+// statusNames maps operational status codes to their names used in diagnostics.
+var statusNames = map[int64]string{
+	Status.Offline:       "Offline",
+	Status.Starting:      "Starting",
+	Status.Started:       "Started",
+	Status.StartFailed:   "StartFailed",
+	Status.Stopping:      "Stopping",
+	Status.StopFailed:    "StopFailed",
+	Status.Creating:      "Creating",
+	Status.Deleting:      "Deleting",
+	Status.Deleted:       "Deleted",
+	Status.Created:       "Created",
+	Status.Suspended:     "Suspended",
+	Status.Suspending:    "Suspending",
+	Status.SuspendFailed: "SuspendFailed",
+	Status.Resuming:      "Resuming",
+	Status.ResumeFailed:  "ResumeFailed",
+	Status.CreateFailed:  "CreateFailed",
+	Status.DeleteFailed:  "DeleteFailed",
+}
+
+// StatusName returns a human-readable representation of an operational status code, e.g. "Started (3)".
+func StatusName(status int64) string {
+	if name, ok := statusNames[status]; ok {
+		return fmt.Sprintf("%s (%d)", name, status)
+	}
+	return fmt.Sprintf("unknown (%d)", status)
+}
+
 // CheckIfVMIsRunning checks if the VM is running.
 // Returns true if the VM is running (OperStatus == Status.Started), otherwise false.
-func CheckIfVMIsRunning(client *http.Client, authCookie, baseURL string, vmID int64) (bool, error) {
-	payload := BuildJSONRPCRequest("vm-get", map[string]interface{}{
-		"id": vmID,
-	})
-
-	vmResp, err := vstack_api.VmGet(payload, authCookie, baseURL, client)
+func CheckIfVMIsRunning(ctx context.Context, client *vstack_api.Client, vmID int64) (bool, error) {
+	status, err := GetVMOperStatus(ctx, client, vmID)
 	if err != nil {
-		return false, fmt.Errorf("failed to get VM status: %w", err)
+		return false, err
 	}
 
 	// Check OperStatus
-	if vmResp.Data.OperStatus == Status.Started {
+	if status == Status.Started {
 		return true, nil
 	}
 
 	return false, nil
 }
 
+// GetVMOperStatus returns the current operational status of the VM, e.g. Status.Suspended.
+func GetVMOperStatus(ctx context.Context, client *vstack_api.Client, vmID int64) (int64, error) {
+	vmResp, err := client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get VM status: %w", err)
+	}
+	return vmResp.Data.OperStatus, nil
+}
+
 // int64NullIfNil converts a pointer to int64 into a Terraform types.Int64.
 // If the pointer is nil, it returns a null Int64; otherwise, it returns the actual value.
 func int64NullIfNil(val *int64) types.Int64 {
@@ -147,20 +164,18 @@ func validateString(value interface{}, fieldName string) error {
 	return nil
 }
 
-// FormatDisks transforms a slice of DiskModel into a slice of maps suitable for API requests.
-// Each disk's properties are converted into key-value pairs expected by the API.
-func FormatDisks(disks []models.DiskModel) []map[string]interface{} {
-	formatted := []map[string]interface{}{}
+// FormatDisks transforms a slice of DiskModel into a slice of DiskParams suitable for API requests.
+// Each disk's properties are converted into the units expected by the API.
+func FormatDisks(disks []models.DiskModel) []vstack_api.DiskParams {
+	formatted := []vstack_api.DiskParams{}
 	for _, disk := range disks {
-		diskMap := map[string]interface{}{
-			"size":       ConvertGbToBytes(disk.Size.ValueInt64()), // Convert size from GB to bytes
-			"slot":       disk.Slot.ValueInt64(),                   // Slot number for the disk
-			"iops_limit": disk.IopsLimit.ValueInt64(),              // IOPS limit for the disk
-			"mbps_limit": disk.MbpsLimit.ValueInt64(),              // MBps limit for the disk
-			"label":      disk.Label.ValueString(),                 // Label/name for the disk
-		}
-
-		formatted = append(formatted, diskMap)
+		formatted = append(formatted, vstack_api.DiskParams{
+			Size:      ConvertGbToBytes(disk.Size.ValueInt64()), // Convert size from GB to bytes
+			Slot:      disk.Slot.ValueInt64(),                   // Slot number for the disk
+			IOPSLimit: disk.IopsLimit.ValueInt64(),              // IOPS limit for the disk
+			MBPSLimit: disk.MbpsLimit.ValueInt64(),              // MBps limit for the disk
+			Label:     disk.Label.ValueString(),                 // Label/name for the disk
+		})
 	}
 	return formatted
 }
@@ -0,0 +1,114 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// Restart policies controlling whether a running VM may be stopped and started again to change its hardware.
+const (
+	RestartPolicyNever    = "never"     // Never stop the VM; the change fails if it cannot be hot-plugged.
+	RestartPolicyIfNeeded = "if_needed" // Stop the VM only if the change cannot be hot-plugged.
+	RestartPolicyAlways   = "always"    // Always stop the VM for the change, without trying to hot-plug it.
+)
+
+// RestartPolicies lists the valid restart policies.
+var RestartPolicies = []string{RestartPolicyNever, RestartPolicyIfNeeded, RestartPolicyAlways}
+
+// HotplugOptions controls how ApplyHardwareChange changes the hardware of a running VM.
+type HotplugOptions struct {
+	Hotplug       bool          // Whether to try the change on the running VM first.
+	RestartPolicy string        // One of RestartPolicies; RestartPolicyIfNeeded is used if it is empty.
+	StatusTimeout time.Duration // The maximum time to wait for the VM to stop or start.
+}
+
+// RestartForbiddenError is returned when a change requires stopping a running VM, but the restart policy forbids it.
+type RestartForbiddenError struct {
+	VmID         int64 // The unique identifier of the VM.
+	HotplugError error // The error of the rejected hot-plug attempt; nil if hot-plug is disabled.
+}
+
+// Error implements the error interface for the RestartForbiddenError struct.
+func (e *RestartForbiddenError) Error() string {
+	if e.HotplugError != nil {
+		return fmt.Sprintf("VM %d rejected the change while running (%s) and restart_policy %q forbids stopping it",
+			e.VmID, e.HotplugError, RestartPolicyNever)
+	}
+	return fmt.Sprintf("VM %d is running, hotplug is disabled and restart_policy %q forbids stopping it",
+		e.VmID, RestartPolicyNever)
+}
+
+// Unwrap returns the error of the rejected hot-plug attempt.
+func (e *RestartForbiddenError) Unwrap() error {
+	return e.HotplugError
+}
+
+// ApplyHardwareChange applies a hardware change, e.g. adding a NIC, to a VM.
+// A stopped VM is changed directly. On a running VM the change is hot-plugged first if opts.Hotplug is set,
+// and the VM is only stopped and started again if vStack rejects it and the restart policy allows it.
+//
+// Parameters:
+// - ctx: The context of the operation; cancelling it aborts the API calls and status polling.
+// - client: The vStack API client used to make API requests.
+// - vmID: The unique identifier of the VM.
+// - opts: The hot-plug mode, the restart policy and the status timeout.
+// - change: The function applying the change; it may be called twice if a hot-plug attempt is rejected.
+//
+// Returns:
+// - Whether the VM was stopped and started again for the change.
+// - An error if the change fails, the restart policy forbids the needed restart or the VM cannot be stopped or started.
+func ApplyHardwareChange(
+	ctx context.Context,
+	client *vstack_api.Client,
+	vmID int64,
+	opts HotplugOptions,
+	change func(ctx context.Context) error,
+) (bool, error) {
+	policy := opts.RestartPolicy
+	if policy == "" {
+		policy = RestartPolicyIfNeeded
+	}
+
+	// 1. A stopped VM is changed directly.
+	running, err := CheckIfVMIsRunning(ctx, client, vmID)
+	if err != nil {
+		return false, fmt.Errorf("ApplyHardwareChange: %w", err)
+	}
+	if !running {
+		return false, change(ctx)
+	}
+
+	// 2. Try the change on the running VM. Only errors returned by vStack mean that the change was rejected
+	// and not applied; other errors, e.g. a lost connection, are returned as is.
+	var hotplugErr error
+	if opts.Hotplug && policy != RestartPolicyAlways {
+		hotplugErr = change(ctx)
+		var apiErr *vstack_api.Error
+		if hotplugErr == nil || !errors.As(hotplugErr, &apiErr) {
+			return false, hotplugErr
+		}
+		tflog.Info(ctx, "vStack rejected the change of the running VM", map[string]any{"vm_id": vmID, "error": hotplugErr.Error()})
+	}
+	if policy == RestartPolicyNever {
+		return false, &RestartForbiddenError{VmID: vmID, HotplugError: hotplugErr}
+	}
+
+	// 3. Stop the VM, apply the change and start the VM again, even if the change fails.
+	if err := PerformAction(ctx, client, vmID, "stop", opts.StatusTimeout); err != nil {
+		return false, fmt.Errorf("ApplyHardwareChange: failed to stop VM before the change: %w", err)
+	}
+	changeErr := change(ctx)
+	if err := PerformAction(ctx, client, vmID, "start", opts.StatusTimeout); err != nil {
+		return true, errors.Join(changeErr, fmt.Errorf("ApplyHardwareChange: failed to start VM after the change: %w", err))
+	}
+	return true, changeErr
+}
@@ -0,0 +1,143 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/vstack_api"
+	"terraform-provider-vstack/internal/vstacktest"
+)
+
+// newFakeVM starts a fake vStack API with one VM and returns the server, an authenticated client and the VM ID.
+func newFakeVM(t *testing.T, description string) (*vstacktest.Server, *vstack_api.Client, int64) {
+	t.Helper()
+	ctx := context.Background()
+
+	server := vstacktest.NewServer()
+	t.Cleanup(server.Close)
+
+	client := vstack_api.NewClient(server.URL, server.Client())
+	client.SetRetryPolicy(vstack_api.RetryPolicy{})
+	if err := client.Auth(ctx, vstack_api.AuthParams{Username: server.Username, Password: server.Password}); err != nil {
+		t.Fatalf("Auth: %v", err)
+	}
+	vm, err := client.VmCreate(ctx, vstack_api.VmCreateParams{
+		Name:        "vm",
+		Description: description,
+		CPUs:        1,
+		RAM:         1 << 30,
+		OsProfile:   "4001",
+	})
+	if err != nil {
+		t.Fatalf("VmCreate: %v", err)
+	}
+	return server, client, vm.Data.ID
+}
+
+// addNic returns a hardware change adding a NIC to the VM.
+func addNic(client *vstack_api.Client, vmID int64) func(context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := client.VmsAddNic(ctx, vstack_api.VmsAddNicParams{ID: vmID, NetworkID: vstacktest.DefaultNetworkID, Slot: 1})
+		return err
+	}
+}
+
+func TestApplyHardwareChangeHotplugsRunningVM(t *testing.T) {
+	server, client, vmID := newFakeVM(t, "")
+	server.AllowHotplug = true
+	ctx := context.Background()
+
+	if err := helper.PerformAction(ctx, client, vmID, "start", 0); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	restarted, err := helper.ApplyHardwareChange(ctx, client, vmID, helper.HotplugOptions{Hotplug: true}, addNic(client, vmID))
+	if err != nil {
+		t.Fatalf("ApplyHardwareChange: %v", err)
+	}
+	if restarted || server.Calls("vms-stop") != 0 {
+		t.Errorf("expected the NIC to be hot-plugged without stopping the VM")
+	}
+}
+
+func TestApplyHardwareChangeFallsBackToRestart(t *testing.T) {
+	server, client, vmID := newFakeVM(t, "")
+	ctx := context.Background()
+
+	if err := helper.PerformAction(ctx, client, vmID, "start", 0); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	restarted, err := helper.ApplyHardwareChange(ctx, client, vmID, helper.HotplugOptions{Hotplug: true}, addNic(client, vmID))
+	if err != nil {
+		t.Fatalf("ApplyHardwareChange: %v", err)
+	}
+	if !restarted || server.Calls("vms-stop") != 1 {
+		t.Errorf("expected the VM to be stopped once after the rejected hot-plug")
+	}
+	if _, operStatus, _ := server.Status(vmID); operStatus != helper.Status.Started {
+		t.Errorf("expected the VM to be started again, got status %d", operStatus)
+	}
+}
+
+func TestApplyHardwareChangeRespectsRestartPolicyNever(t *testing.T) {
+	server, client, vmID := newFakeVM(t, "")
+	ctx := context.Background()
+
+	if err := helper.PerformAction(ctx, client, vmID, "start", 0); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	opts := helper.HotplugOptions{Hotplug: true, RestartPolicy: helper.RestartPolicyNever}
+	_, err := helper.ApplyHardwareChange(ctx, client, vmID, opts, addNic(client, vmID))
+	var forbidden *helper.RestartForbiddenError
+	if !errors.As(err, &forbidden) || forbidden.HotplugError == nil {
+		t.Fatalf("expected a RestartForbiddenError with the hot-plug error, got %v", err)
+	}
+	if server.Calls("vms-stop") != 0 {
+		t.Errorf("expected the VM not to be stopped")
+	}
+}
+
+func TestApplyHardwareChangeRestartsForBootMedia(t *testing.T) {
+	_, client, vmID := newFakeVM(t, "")
+	ctx := context.Background()
+
+	if err := helper.PerformAction(ctx, client, vmID, "start", 0); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	// The boot media of a running VM cannot be changed, its CPUs can.
+	bootMedia, cpus := int64(2), int64(4)
+	set := func(params vstack_api.VmSetVmParams) error {
+		_, err := client.VmSet(ctx, vstack_api.VmSetParams{ID: vmID, VmParams: params})
+		return err
+	}
+	if err := set(vstack_api.VmSetVmParams{BootMedia: &bootMedia}); err == nil {
+		t.Error("expected changing the boot media of a running VM to fail")
+	}
+	if err := set(vstack_api.VmSetVmParams{CPUs: &cpus}); err != nil {
+		t.Errorf("expected changing the CPUs of a running VM to succeed, got %v", err)
+	}
+
+	// With a restart allowed, the VM is stopped for the change and started again.
+	restarted, err := helper.ApplyHardwareChange(ctx, client, vmID,
+		helper.HotplugOptions{RestartPolicy: helper.RestartPolicyIfNeeded},
+		func(ctx context.Context) error { return set(vstack_api.VmSetVmParams{BootMedia: &bootMedia}) })
+	if err != nil || !restarted {
+		t.Fatalf("expected the VM to be restarted for the change, got %v, %v", restarted, err)
+	}
+	resp, err := client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
+	if err != nil {
+		t.Fatalf("VmGet: %v", err)
+	}
+	if resp.Data.BootMediaID != bootMedia || resp.Data.OperStatus != helper.Status.Started {
+		t.Errorf("expected a running VM with boot media %d, got boot media %d and status %d",
+			bootMedia, resp.Data.BootMediaID, resp.Data.OperStatus)
+	}
+}
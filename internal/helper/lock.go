@@ -0,0 +1,200 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultLockTimeout is the default time to wait for the lock of a VM held by another operation.
+const DefaultLockTimeout = 15 * time.Minute
+
+// lockReleaseTimeout bounds the time spent releasing a lock, even if the operation context is done.
+const lockReleaseTimeout = time.Minute
+
+// LockPollInterval is the interval between two attempts to acquire a VM lock held by another process.
+var LockPollInterval = 2 * time.Second
+
+// vmLocks stores a chan struct{} with a capacity of 1 for each VM by its ID; a VM is locked
+// inside the provider process while its channel holds a value.
+var vmLocks sync.Map // map[int64]chan struct{}.
+
+// LockHolder identifies the provider process and the operation holding a VM lock.
+type LockHolder struct {
+	ID        string    `json:"id"`                  // Unique identifier of the lock acquisition.
+	Owner     string    `json:"owner"`               // User and host of the process, e.g. "ci@runner-1".
+	PID       int       `json:"pid"`                 // Process ID of the provider.
+	Workspace string    `json:"workspace,omitempty"` // Terraform workspace from TF_WORKSPACE, if set.
+	Operation string    `json:"operation"`           // Operation holding the lock, e.g. "vstack_nic create".
+	Acquired  time.Time `json:"acquired"`            // Time the lock was acquired.
+	Expires   time.Time `json:"expires"`             // Time a lease expires unless renewed; zero for other backends.
+}
+
+// String returns a human-readable description of the holder for diagnostics.
+func (h LockHolder) String() string {
+	details := []string{fmt.Sprintf("pid %d", h.PID)}
+	if h.Workspace != "" {
+		details = append(details, fmt.Sprintf("workspace %q", h.Workspace))
+	}
+	if h.Operation != "" {
+		details = append(details, fmt.Sprintf("operation %q", h.Operation))
+	}
+	if !h.Acquired.IsZero() {
+		details = append(details, "since "+h.Acquired.UTC().Format(time.RFC3339))
+	}
+	if !h.Expires.IsZero() {
+		details = append(details, "lease expires "+h.Expires.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s (%s)", h.Owner, strings.Join(details, ", "))
+}
+
+// newLockHolder describes the current process as the holder of a lock for operation.
+func newLockHolder(operation string) LockHolder {
+	owner := "unknown"
+	if u, err := user.Current(); err == nil {
+		owner = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		owner += "@" + host
+	}
+
+	return LockHolder{
+		ID:        uuid.NewString(),
+		Owner:     owner,
+		PID:       os.Getpid(),
+		Workspace: os.Getenv("TF_WORKSPACE"),
+		Operation: operation,
+		Acquired:  time.Now(),
+	}
+}
+
+// VMLockBackend shares VM locks between provider processes, e.g. parallel Terraform runs or workspaces.
+type VMLockBackend interface {
+	// Name returns the name of the backend used in diagnostics, e.g. "file".
+	Name() string
+
+	// TryLock attempts to acquire the lock of a VM for holder without waiting.
+	// On success it returns the function releasing the lock. If the lock is held by another process,
+	// it returns a nil release function and the current holder, if known.
+	TryLock(ctx context.Context, vmID int64, holder LockHolder) (func(context.Context) error, *LockHolder, error)
+}
+
+// VMLockConfig configures how GetVMLock serialises operations on a VM.
+type VMLockConfig struct {
+	Backend VMLockBackend // Backend shared between processes; nil locks the VM inside the provider process only.
+	Timeout time.Duration // Maximum time to wait for the lock; DefaultLockTimeout is used if it is not positive.
+}
+
+// LockTimeoutError is returned when the lock of a VM cannot be acquired in time.
+type LockTimeoutError struct {
+	VmID    int64         // The unique identifier of the VM.
+	Backend string        // The name of the lock backend.
+	Holder  *LockHolder   // The last observed holder of the lock; nil if unknown.
+	Timeout time.Duration // The time waited for the lock.
+}
+
+// Error implements the error interface for the LockTimeoutError struct.
+func (e *LockTimeoutError) Error() string {
+	holder := "another operation of this provider"
+	if e.Holder != nil {
+		holder = e.Holder.String()
+	}
+	return fmt.Sprintf("timed out after %s waiting for the %s lock of VM %d held by %s",
+		e.Timeout.Round(time.Millisecond), e.Backend, e.VmID, holder)
+}
+
+// GetVMLock acquires the lock of a VM and returns the function releasing it.
+// The VM is always locked inside the provider process; with a backend in cfg it is also locked
+// for other provider processes sharing the backend.
+//
+// Parameters:
+// - ctx: The context of the operation; cancelling it stops waiting.
+// - cfg: The lock backend and the maximum time to wait for the lock.
+// - vmID: The unique identifier of the VM.
+// - operation: The operation requesting the lock, shown to other processes waiting for it, e.g. "vstack_vm update".
+//
+// Returns:
+// - The function releasing the lock; it must be called exactly once.
+// - An error, e.g. a LockTimeoutError naming the holder, if the lock is not acquired.
+func GetVMLock(ctx context.Context, cfg VMLockConfig, vmID int64, operation string) (func(), error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	backend := "in-process"
+	if cfg.Backend != nil {
+		backend = cfg.Backend.Name()
+	}
+
+	lockCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// 1. Lock the VM inside the provider process.
+	actual, _ := vmLocks.LoadOrStore(vmID, make(chan struct{}, 1))
+	local, ok := actual.(chan struct{})
+	if !ok {
+		return nil, fmt.Errorf("GetVMLock: unexpected type in vmLocks for vmID %d", vmID)
+	}
+	select {
+	case local <- struct{}{}:
+	case <-lockCtx.Done():
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("GetVMLock: %w", ctx.Err())
+		}
+		return nil, &LockTimeoutError{VmID: vmID, Backend: backend, Timeout: timeout}
+	}
+	unlockLocal := func() { <-local }
+
+	if cfg.Backend == nil {
+		return unlockLocal, nil
+	}
+
+	// 2. Lock the VM for other processes, polling while another process holds the lock.
+	holder := newLockHolder(operation)
+	for {
+		release, current, err := cfg.Backend.TryLock(lockCtx, vmID, holder)
+		if err != nil {
+			unlockLocal()
+			if lockCtx.Err() != nil && ctx.Err() == nil {
+				return nil, &LockTimeoutError{VmID: vmID, Backend: backend, Holder: current, Timeout: timeout}
+			}
+			return nil, fmt.Errorf("GetVMLock: failed to acquire the %s lock of VM %d: %w", backend, vmID, err)
+		}
+		if release != nil {
+			tflog.Debug(ctx, "Acquired VM lock", map[string]any{"vm_id": vmID, "backend": backend, "operation": operation})
+			return func() {
+				releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lockReleaseTimeout)
+				defer cancel()
+				if err := release(releaseCtx); err != nil {
+					tflog.Warn(ctx, "Failed to release VM lock", map[string]any{"vm_id": vmID, "backend": backend, "error": err.Error()})
+				}
+				unlockLocal()
+			}, nil
+		}
+
+		if current != nil {
+			tflog.Info(ctx, "Waiting for VM lock", map[string]any{"vm_id": vmID, "backend": backend, "holder": current.String()})
+		}
+		timer := time.NewTimer(LockPollInterval)
+		select {
+		case <-lockCtx.Done():
+			timer.Stop()
+			unlockLocal()
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("GetVMLock: %w", ctx.Err())
+			}
+			return nil, &LockTimeoutError{VmID: vmID, Backend: backend, Holder: current, Timeout: timeout}
+		case <-timer.C:
+		}
+	}
+}
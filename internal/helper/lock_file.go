@@ -0,0 +1,114 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// lockFileHolderMaxSize is the maximum size of the holder record read from a lock file.
+// On Windows the locked byte range starts right after it, so that waiting processes can still read the holder.
+const lockFileHolderMaxSize = 4096
+
+// DefaultLockDir returns the directory used by the file lock backend when none is configured.
+// Provider processes of the same machine share it.
+func DefaultLockDir() string {
+	return filepath.Join(os.TempDir(), "terraform-provider-vstack-locks")
+}
+
+// FileLockBackend locks VMs with OS file locks in a directory shared by the provider processes,
+// e.g. a directory of a CI runner or a network file system supporting locks.
+// A lock is released by the OS if its process dies.
+type FileLockBackend struct {
+	Dir string // The directory holding one lock file per VM.
+}
+
+// NewFileLockBackend returns a file lock backend using dir, creating it if needed.
+// DefaultLockDir is used if dir is empty.
+func NewFileLockBackend(dir string) (*FileLockBackend, error) {
+	if dir == "" {
+		dir = DefaultLockDir()
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("NewFileLockBackend: %w", err)
+	}
+	return &FileLockBackend{Dir: dir}, nil
+}
+
+// Name implements VMLockBackend.
+func (b *FileLockBackend) Name() string {
+	return "file"
+}
+
+// TryLock implements VMLockBackend. The holder is written to the lock file, so that waiting
+// processes can name it in diagnostics.
+func (b *FileLockBackend) TryLock(_ context.Context, vmID int64, holder LockHolder) (func(context.Context) error, *LockHolder, error) {
+	path := filepath.Join(b.Dir, fmt.Sprintf("vm-%d.lock", vmID))
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("FileLockBackend.TryLock: %w", err)
+	}
+
+	locked, err := tryLockFile(f)
+	if err != nil || !locked {
+		current := readLockHolder(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, current, fmt.Errorf("FileLockBackend.TryLock: %s: %w", path, err)
+		}
+		return nil, current, nil
+	}
+
+	if err := writeLockHolder(f, holder); err != nil {
+		_ = unlockFile(f)
+		_ = f.Close()
+		return nil, nil, fmt.Errorf("FileLockBackend.TryLock: %s: %w", path, err)
+	}
+
+	release := func(context.Context) error {
+		truncErr := f.Truncate(0)
+		unlockErr := unlockFile(f)
+		closeErr := f.Close()
+		for _, err := range []error{unlockErr, truncErr, closeErr} {
+			if err != nil {
+				return fmt.Errorf("FileLockBackend: failed to release %s: %w", path, err)
+			}
+		}
+		return nil
+	}
+	return release, nil, nil
+}
+
+// readLockHolder returns the holder recorded in a lock file, or nil if it cannot be read.
+func readLockHolder(f *os.File) *LockHolder {
+	data, err := io.ReadAll(io.NewSectionReader(f, 0, lockFileHolderMaxSize))
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	var holder LockHolder
+	if err := json.Unmarshal(data, &holder); err != nil {
+		return nil
+	}
+	return &holder
+}
+
+// writeLockHolder replaces the content of a locked lock file with the holder.
+func writeLockHolder(f *os.File, holder LockHolder) error {
+	data, err := json.Marshal(holder)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := f.WriteAt(data, 0); err != nil {
+		return err
+	}
+	return f.Sync()
+}
@@ -0,0 +1,22 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package helper
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// tryLockFile reports that file locks are not supported on this platform.
+func tryLockFile(*os.File) (bool, error) {
+	return false, fmt.Errorf("file locks are not supported on %s", runtime.GOOS)
+}
+
+// unlockFile reports that file locks are not supported on this platform.
+func unlockFile(*os.File) error {
+	return fmt.Errorf("file locks are not supported on %s", runtime.GOOS)
+}
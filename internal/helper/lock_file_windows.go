@@ -0,0 +1,34 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+
+package helper
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// tryLockFile locks a byte range of f exclusively without waiting.
+// It returns false if another open file holds the lock.
+func tryLockFile(f *os.File) (bool, error) {
+	overlapped := &windows.Overlapped{Offset: lockFileHolderMaxSize}
+	err := windows.LockFileEx(windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, overlapped)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// unlockFile releases the byte range locked by tryLockFile.
+func unlockFile(f *os.File) error {
+	overlapped := &windows.Overlapped{Offset: lockFileHolderMaxSize}
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, overlapped)
+}
@@ -0,0 +1,200 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// DefaultLeaseTTL is the default time after which a lease that is no longer renewed can be taken over,
+// e.g. because its provider process was killed.
+const DefaultLeaseTTL = 5 * time.Minute
+
+// Delimiters of the lease record appended to the VM description by the lease backend.
+const (
+	leaseMarkerPrefix = "\n[terraform-lock "
+	leaseMarkerSuffix = "]"
+)
+
+// LeaseSettleDelay is the time the lease backend waits after writing a lease before checking that it has
+// not been overwritten by another process writing at the same time.
+var LeaseSettleDelay = time.Second
+
+// LeaseLockBackend locks VMs with leases stored in the VM description, so that provider processes
+// on different machines sharing only the vStack API exclude each other. A lease is renewed while
+// the lock is held and expires after TTL if its process dies.
+//
+// vStack has no atomic compare-and-set for the description, so two processes writing a lease at
+// the same moment are resolved on a best-effort basis by re-reading it after LeaseSettleDelay.
+type LeaseLockBackend struct {
+	Client *vstack_api.Client // The vStack API client used to read and write the VM description.
+	TTL    time.Duration      // The lifetime of a lease that is not renewed.
+}
+
+// NewLeaseLockBackend returns a lease lock backend; DefaultLeaseTTL is used if ttl is not positive.
+func NewLeaseLockBackend(client *vstack_api.Client, ttl time.Duration) *LeaseLockBackend {
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+	return &LeaseLockBackend{Client: client, TTL: ttl}
+}
+
+// Name implements VMLockBackend.
+func (b *LeaseLockBackend) Name() string {
+	return "lease"
+}
+
+// StripLeaseMarker removes the lease record of the lease lock backend from a VM description.
+func StripLeaseMarker(description string) string {
+	idx := strings.LastIndex(description, leaseMarkerPrefix)
+	if idx < 0 || !strings.HasSuffix(description, leaseMarkerSuffix) {
+		return description
+	}
+	return description[:idx]
+}
+
+// parseLease returns the lease recorded in a VM description, or nil if there is none.
+func parseLease(description string) *LockHolder {
+	idx := strings.LastIndex(description, leaseMarkerPrefix)
+	if idx < 0 || !strings.HasSuffix(description, leaseMarkerSuffix) {
+		return nil
+	}
+	record := strings.TrimSuffix(description[idx+len(leaseMarkerPrefix):], leaseMarkerSuffix)
+
+	var holder LockHolder
+	if err := json.Unmarshal([]byte(record), &holder); err != nil {
+		return nil
+	}
+	return &holder
+}
+
+// TryLock implements VMLockBackend.
+func (b *LeaseLockBackend) TryLock(ctx context.Context, vmID int64, holder LockHolder) (func(context.Context) error, *LockHolder, error) {
+	// 1. Fail if another process holds a lease that has not expired.
+	description, err := b.description(ctx, vmID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("LeaseLockBackend.TryLock: %w", err)
+	}
+	if current := parseLease(description); current != nil && current.ID != holder.ID && time.Now().Before(current.Expires) {
+		return nil, current, nil
+	}
+
+	// 2. Write the lease and check that it has not been overwritten by a concurrent writer.
+	holder.Expires = time.Now().Add(b.TTL)
+	if err := b.writeLease(ctx, vmID, StripLeaseMarker(description), &holder); err != nil {
+		return nil, nil, fmt.Errorf("LeaseLockBackend.TryLock: %w", err)
+	}
+	timer := time.NewTimer(LeaseSettleDelay)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return nil, nil, fmt.Errorf("LeaseLockBackend.TryLock: %w", ctx.Err())
+	case <-timer.C:
+	}
+	if description, err = b.description(ctx, vmID); err != nil {
+		return nil, nil, fmt.Errorf("LeaseLockBackend.TryLock: %w", err)
+	}
+	if current := parseLease(description); current == nil || current.ID != holder.ID {
+		return nil, current, nil
+	}
+
+	// 3. Renew the lease until it is released. Renewing also restores a lease removed by a "vm-set"
+	// of the description during the locked operation.
+	renewCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(b.TTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+				if !b.renew(renewCtx, vmID, &holder) {
+					return
+				}
+			}
+		}
+	}()
+
+	release := func(ctx context.Context) error {
+		stop()
+		<-done
+
+		description, err := b.description(ctx, vmID)
+		if err != nil {
+			// The locked operation may have removed the VM.
+			if vstack_api.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("LeaseLockBackend: failed to release the lease: %w", err)
+		}
+		if current := parseLease(description); current == nil || current.ID != holder.ID {
+			return nil
+		}
+		if err := b.writeLease(ctx, vmID, StripLeaseMarker(description), nil); err != nil {
+			return fmt.Errorf("LeaseLockBackend: failed to release the lease: %w", err)
+		}
+		return nil
+	}
+	return release, nil, nil
+}
+
+// renew extends the lease of holder. It returns false if the lease was taken over by another process.
+func (b *LeaseLockBackend) renew(ctx context.Context, vmID int64, holder *LockHolder) bool {
+	description, err := b.description(ctx, vmID)
+	if err != nil {
+		tflog.Warn(ctx, "Failed to renew VM lease", map[string]any{"vm_id": vmID, "error": err.Error()})
+		return true
+	}
+	if current := parseLease(description); current != nil && current.ID != holder.ID {
+		tflog.Warn(ctx, "VM lease was taken over by another process", map[string]any{"vm_id": vmID, "holder": current.String()})
+		return false
+	}
+
+	holder.Expires = time.Now().Add(b.TTL)
+	if err := b.writeLease(ctx, vmID, StripLeaseMarker(description), holder); err != nil {
+		tflog.Warn(ctx, "Failed to renew VM lease", map[string]any{"vm_id": vmID, "error": err.Error()})
+	}
+	return true
+}
+
+// description returns the current description of a VM, including the lease record.
+func (b *LeaseLockBackend) description(ctx context.Context, vmID int64) (string, error) {
+	resp, err := b.Client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
+	if err != nil {
+		return "", err
+	}
+	if resp.Data.Description == nil {
+		return "", nil
+	}
+	return *resp.Data.Description, nil
+}
+
+// writeLease sets the VM description to base followed by the lease record of holder; a nil holder removes the lease.
+func (b *LeaseLockBackend) writeLease(ctx context.Context, vmID int64, base string, holder *LockHolder) error {
+	description := base
+	if holder != nil {
+		record, err := json.Marshal(holder)
+		if err != nil {
+			return err
+		}
+		description += leaseMarkerPrefix + string(record) + leaseMarkerSuffix
+	}
+
+	_, err := b.Client.VmSet(ctx, vstack_api.VmSetParams{
+		ID:       vmID,
+		VmParams: vstack_api.VmSetVmParams{Description: &description},
+	})
+	return err
+}
@@ -0,0 +1,139 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+func TestGetVMLockTimesOutInProcess(t *testing.T) {
+	ctx := context.Background()
+	cfg := helper.VMLockConfig{Timeout: 50 * time.Millisecond}
+
+	unlock, err := helper.GetVMLock(ctx, cfg, 1, "first")
+	if err != nil {
+		t.Fatalf("GetVMLock: %v", err)
+	}
+
+	var timeoutErr *helper.LockTimeoutError
+	if _, err := helper.GetVMLock(ctx, cfg, 1, "second"); !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a LockTimeoutError, got %v", err)
+	}
+
+	unlock()
+	unlock, err = helper.GetVMLock(ctx, cfg, 1, "third")
+	if err != nil {
+		t.Fatalf("GetVMLock after unlock: %v", err)
+	}
+	unlock()
+}
+
+func TestFileLockBackendNamesHolder(t *testing.T) {
+	helper.LockPollInterval = 10 * time.Millisecond
+	ctx := context.Background()
+
+	backend, err := helper.NewFileLockBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileLockBackend: %v", err)
+	}
+
+	// Another process holding the lock file is simulated with a separate open file.
+	release, _, err := backend.TryLock(ctx, 2, helper.LockHolder{ID: "other", Owner: "ci@runner-1", PID: 42, Operation: "vstack_nic create"})
+	if err != nil || release == nil {
+		t.Fatalf("TryLock: release=%v, err=%v", release != nil, err)
+	}
+
+	_, err = helper.GetVMLock(ctx, helper.VMLockConfig{Backend: backend, Timeout: 50 * time.Millisecond}, 2, "vstack_vm update")
+	var timeoutErr *helper.LockTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a LockTimeoutError, got %v", err)
+	}
+	if timeoutErr.Holder == nil || timeoutErr.Holder.Owner != "ci@runner-1" || timeoutErr.Holder.Operation != "vstack_nic create" {
+		t.Errorf("expected the error to name the holder, got %v", err)
+	}
+
+	if err := release(ctx); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	unlock, err := helper.GetVMLock(ctx, helper.VMLockConfig{Backend: backend, Timeout: time.Second}, 2, "vstack_vm update")
+	if err != nil {
+		t.Fatalf("GetVMLock after release: %v", err)
+	}
+	unlock()
+}
+
+func TestLeaseLockBackendKeepsDescription(t *testing.T) {
+	helper.LeaseSettleDelay = 0
+	ctx := context.Background()
+
+	_, client, vmID := newFakeVM(t, "web server")
+
+	backend := helper.NewLeaseLockBackend(client, time.Minute)
+	release, _, err := backend.TryLock(ctx, vmID, helper.LockHolder{ID: "a", Owner: "ci@runner-1", Operation: "vstack_nic create"})
+	if err != nil || release == nil {
+		t.Fatalf("TryLock: release=%v, err=%v", release != nil, err)
+	}
+
+	resp, err := client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
+	if err != nil {
+		t.Fatalf("VmGet: %v", err)
+	}
+	if got := helper.StripLeaseMarker(*resp.Data.Description); got != "web server" {
+		t.Errorf("expected the description without the lease to be kept, got %q", got)
+	}
+
+	_, current, err := backend.TryLock(ctx, vmID, helper.LockHolder{ID: "b", Owner: "ci@runner-2"})
+	if err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+	if current == nil || current.Owner != "ci@runner-1" {
+		t.Errorf("expected the lease to be held by ci@runner-1, got %v", current)
+	}
+
+	if err := release(ctx); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	resp, err = client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
+	if err != nil {
+		t.Fatalf("VmGet: %v", err)
+	}
+	if got := *resp.Data.Description; got != "web server" {
+		t.Errorf("expected the lease to be removed from the description, got %q", got)
+	}
+}
+
+func TestLeaseLockBackendReleaseFailsOnAPIError(t *testing.T) {
+	helper.LeaseSettleDelay = 0
+	ctx := context.Background()
+
+	server, client, vmID := newFakeVM(t, "")
+	backend := helper.NewLeaseLockBackend(client, time.Minute)
+	lock := func() func(context.Context) error {
+		release, _, err := backend.TryLock(ctx, vmID, helper.LockHolder{ID: "a", Owner: "ci@runner-1"})
+		if err != nil || release == nil {
+			t.Fatalf("TryLock: release=%v, err=%v", release != nil, err)
+		}
+		return release
+	}
+
+	// A lease that cannot be removed is reported, since it blocks other runs until it expires.
+	release := lock()
+	server.InjectError("vm-get", 409, "VM is locked", 1)
+	if err := release(ctx); err == nil {
+		t.Error("expected the release to fail")
+	}
+
+	// A VM removed by the locked operation has no lease left to remove.
+	release = lock()
+	server.InjectError("vm-get", 404, "VM not found", 1)
+	if err := release(ctx); err != nil {
+		t.Errorf("expected the release of a removed VM to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// VMNicAttrTypes are the attribute types of an element of the computed "nics" list of a VM.
+var VMNicAttrTypes = map[string]attr.Type{
+	"slot":       types.Int64Type,
+	"port_id":    types.Int64Type,
+	"network_id": types.Int64Type,
+	"mac":        types.StringType,
+	"address":    types.StringType,
+}
+
+// MapNicsToModel maps the network ports of a VM to the computed "nics" list of the VM.
+// The NICs themselves are managed by vstack_nic resources; the list only reports them,
+// e.g. the NICs a clone got from its source.
+//
+// Parameters:
+// - ports: The network ports of the VM fetched from the API.
+//
+// Returns:
+// - A types.List of objects with VMNicAttrTypes, ordered as returned by the API.
+// - An error if a list element cannot be built.
+func MapNicsToModel(ports []vstack_api.NetworkPort) (types.List, error) {
+	elemType := types.ObjectType{AttrTypes: VMNicAttrTypes}
+	elems := make([]attr.Value, 0, len(ports))
+	for i, port := range ports {
+		nic, diags := types.ObjectValue(VMNicAttrTypes, map[string]attr.Value{
+			"slot":       types.Int64Value(port.Slot),
+			"port_id":    types.Int64Value(port.PortID),
+			"network_id": types.Int64Value(port.NetworkID),
+			"mac":        types.StringValue(port.MAC),
+			"address":    types.StringValue(port.Address),
+		})
+		if diags.HasError() {
+			return types.ListNull(elemType), fmt.Errorf("MapNicsToModel: failed to create NIC[%d]: %v", i, diags)
+		}
+		elems = append(elems, nic)
+	}
+
+	nics, diags := types.ListValue(elemType, elems)
+	if diags.HasError() {
+		return types.ListNull(elemType), fmt.Errorf("MapNicsToModel: %v", diags)
+	}
+	return nics, nil
+}
@@ -91,7 +91,8 @@ func MapRespToState(resp vstack_api.VmGetResult, state models.VMResourceModel) (
 	state.Name = types.StringValue(resp.Data.Name)
 	desc := ""
 	if resp.Data.Description != nil {
-		desc = *resp.Data.Description
+		// A lease of the lease lock backend is not part of the configured description.
+		desc = StripLeaseMarker(*resp.Data.Description)
 	}
 
 	if desc == "" {
@@ -119,7 +120,7 @@ func MapRespToState(resp vstack_api.VmGetResult, state models.VMResourceModel) (
 	state.PoolSelector = types.StringValue(resp.Data.Pool)
 	state.Status = types.Int64Value(resp.Data.Status)
 	state.OperStatus = types.Int64Value(resp.Data.OperStatus)
-	state.Action = types.StringValue(getActionFromStatus(resp.Data.OperStatus))
+	state.Action = types.StringValue(getActionFromStatus(resp.Data.OperStatus, state.Action.ValueString()))
 
 	// Handle Guest (nullable)
 	if resp.Data.Guest != nil {
@@ -184,24 +185,83 @@ func MapRespToState(resp vstack_api.VmGetResult, state models.VMResourceModel) (
 		state.Disks = disks
 	}
 
+	// Map the NICs from the API response to the Terraform state.
+	if nics, err := MapNicsToModel(resp.Data.NetworkPorts); err != nil {
+		return state, err
+	} else {
+		state.Nics = nics
+	}
+
 	return state, nil
 }
 
 // getActionFromStatus determines the appropriate action based on the VM's operational status.
-// It returns "start" if the VM is started, "stop" if the VM is offline or created, and an empty string otherwise.
+// It returns "start" if the VM is started, "stop" if the VM is offline or created, "suspend" if the VM is
+// suspended, and an empty string otherwise. An offline VM keeps the "shutdown" or "poweroff" action it was
+// stopped with, so that these actions do not show a difference to "stop".
 //
 // Parameters:
 // - status: The operational status code of the VM.
+// - current: The action currently in the state or plan.
 //
 // Returns:
 // - A string representing the action to be performed.
-func getActionFromStatus(status int64) string {
+func getActionFromStatus(status int64, current string) string {
 	switch status {
 	case Status.Started:
 		return "start"
 	case Status.Offline, Status.Created:
+		if current == "shutdown" || current == "poweroff" {
+			return current
+		}
 		return "stop"
+	case Status.Suspended:
+		return "suspend"
 	default:
 		return ""
 	}
 }
+
+// MapRespToDataSourceModel maps the API response (VmGetResult) to a VM of the vstack_vm_get and
+// vstack_vms data sources. Unlike MapRespToState, it has no configuration to keep guest settings from.
+//
+// Parameters:
+// - resp: The API response of type vstack_api.VmGetResult containing VM data.
+//
+// Returns:
+// - The models.VMDataSourceModel reflecting the API response.
+// - An error if any validation fails during the mapping process.
+func MapRespToDataSourceModel(resp vstack_api.VmGetResult) (models.VMDataSourceModel, error) {
+	state, err := MapRespToState(resp, models.VMResourceModel{})
+	if err != nil {
+		return models.VMDataSourceModel{}, err
+	}
+
+	return models.VMDataSourceModel{
+		ID:              state.ID,
+		Name:            state.Name,
+		Description:     state.Description,
+		CPUs:            state.CPUs,
+		RAM:             state.RAM,
+		CPUPriority:     state.CPUPriority,
+		BootMedia:       state.BootMedia,
+		VcpuClass:       state.VcpuClass,
+		OsType:          state.OsType,
+		OsProfile:       state.OsProfile,
+		VdcID:           state.VdcID,
+		AdminStatus:     state.AdminStatus,
+		Node:            state.Node,
+		Uefi:            state.Uefi,
+		CreateCompleted: state.CreateCompleted,
+		Locked:          state.Locked,
+		RootDataset:     state.RootDataset,
+		RootDatasetName: state.RootDatasetName,
+		PoolSelector:    state.PoolSelector,
+		Status:          state.Status,
+		OperStatus:      state.OperStatus,
+		Action:          state.Action,
+		Guest:           state.Guest,
+		Disks:           state.Disks,
+		Nics:            state.Nics,
+	}, nil
+}
@@ -0,0 +1,36 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import "testing"
+
+func TestGetActionFromStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int64
+		current string
+		want    string
+	}{
+		{name: "started", status: Status.Started, current: "stop", want: "start"},
+		{name: "started after resume", status: Status.Started, current: "suspend", want: "start"},
+		{name: "suspended", status: Status.Suspended, current: "start", want: "suspend"},
+		{name: "suspended on import", status: Status.Suspended, current: "", want: "suspend"},
+		{name: "suspended keeps suspend", status: Status.Suspended, current: "suspend", want: "suspend"},
+		{name: "suspending", status: Status.Suspending, current: "suspend", want: ""},
+		{name: "offline", status: Status.Offline, current: "start", want: "stop"},
+		{name: "offline after suspend", status: Status.Offline, current: "suspend", want: "stop"},
+		{name: "offline keeps shutdown", status: Status.Offline, current: "shutdown", want: "shutdown"},
+		{name: "offline keeps poweroff", status: Status.Offline, current: "poweroff", want: "poweroff"},
+		{name: "created keeps shutdown", status: Status.Created, current: "shutdown", want: "shutdown"},
+		{name: "started after shutdown", status: Status.Started, current: "shutdown", want: "start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getActionFromStatus(tt.status, tt.current); got != tt.want {
+				t.Errorf("getActionFromStatus(%d, %q) = %q, want %q", tt.status, tt.current, got, tt.want)
+			}
+		})
+	}
+}
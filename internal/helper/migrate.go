@@ -0,0 +1,45 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// MigrateVM moves a VM to another node with "vms-migrate" and polls "vm-get" until the VM is reported on it.
+// A running VM is migrated live if live is set; otherwise the VM must not be running.
+//
+// Parameters:
+// - ctx: The context of the operation; cancelling it aborts the API calls and stops waiting.
+// - client: The vStack API client used to make API requests.
+// - vmID: The unique identifier of the VM.
+// - node: The ID of the node to move the VM to.
+// - live: Whether to migrate the running VM without stopping it.
+// - timeout: The maximum time to wait for the migration; DefaultStatusTimeout is used if it is not positive, an earlier ctx deadline shortens it.
+//
+// Returns:
+// - An error if the migration fails, the VM enters a failure status or the VM is not on the node in time (*StatusTimeoutError).
+func MigrateVM(ctx context.Context, client *vstack_api.Client, vmID, node int64, live bool, timeout time.Duration) error {
+	// 1. Start the migration
+	if _, err := client.VmsMigrate(ctx, vstack_api.VmsMigrateParams{ID: vmID, Node: node, Live: live}); err != nil {
+		return fmt.Errorf("MigrateVM: %w", err)
+	}
+
+	// 2. Wait until the VM is reported on the target node
+	expected := StatusTimeoutError{VmID: vmID, Node: node}
+	_, err := pollVM(ctx, client, expected, timeout, func(resp vstack_api.VmGetResult) (bool, error) {
+		if resp.Data.Node == node {
+			return true, nil
+		}
+		return false, CheckVMFailure(resp)
+	})
+	if err != nil {
+		return fmt.Errorf("MigrateVM: %w", err)
+	}
+	return nil
+}
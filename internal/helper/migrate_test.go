@@ -0,0 +1,49 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+func TestMigrateVMWaitsForNode(t *testing.T) {
+	interval := helper.StatusPollInterval
+	helper.StatusPollInterval = time.Millisecond
+	t.Cleanup(func() { helper.StatusPollInterval = interval })
+
+	server, client, vmID := newFakeVM(t, "")
+	server.TransitionPolls = 2
+	ctx := context.Background()
+
+	// A VM that is not running can only be migrated offline.
+	if err := helper.MigrateVM(ctx, client, vmID, 2, true, time.Second); err == nil {
+		t.Error("expected migrating a VM that is not running live to fail")
+	}
+	if err := helper.MigrateVM(ctx, client, vmID, 2, false, time.Second); err != nil {
+		t.Fatalf("offline migration: %v", err)
+	}
+
+	// A running VM is migrated live and keeps running.
+	if err := helper.PerformAction(ctx, client, vmID, "start", time.Second); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := helper.MigrateVM(ctx, client, vmID, 3, false, time.Second); err == nil {
+		t.Error("expected migrating a running VM offline to fail")
+	}
+	if err := helper.MigrateVM(ctx, client, vmID, 3, true, time.Second); err != nil {
+		t.Fatalf("live migration: %v", err)
+	}
+	resp, err := client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
+	if err != nil {
+		t.Fatalf("VmGet: %v", err)
+	}
+	if resp.Data.Node != 3 || resp.Data.OperStatus != helper.Status.Started {
+		t.Errorf("expected a running VM on node 3, got node %d and status %d", resp.Data.Node, resp.Data.OperStatus)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// NetworkFilter selects a network by its ID, name or VDC. Zero values match any network.
+type NetworkFilter struct {
+	ID    int64
+	Name  string
+	VdcID int64
+}
+
+// String returns a human-readable description of the filter for error messages.
+func (f NetworkFilter) String() string {
+	var parts []string
+	if f.ID != 0 {
+		parts = append(parts, fmt.Sprintf("id=%d", f.ID))
+	}
+	if f.Name != "" {
+		parts = append(parts, fmt.Sprintf("name=%q", f.Name))
+	}
+	if f.VdcID != 0 {
+		parts = append(parts, fmt.Sprintf("vdc_id=%d", f.VdcID))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// matches reports whether the network matches the filter.
+func (f NetworkFilter) matches(network vstack_api.Network) bool {
+	return (f.ID == 0 || network.ID == f.ID) &&
+		(f.Name == "" || network.Name == f.Name) &&
+		(f.VdcID == 0 || network.VdcID == f.VdcID)
+}
+
+// FindNetwork returns the only network matching the filter.
+// A network selected by ID is retrieved with "network-get", otherwise the networks are listed with "networks-list".
+//
+// Parameters:
+// - ctx: The context of the operation.
+// - client: The vStack API client used to make API requests.
+// - filter: The ID, name or VDC of the network; at least one of them must be set.
+//
+// Returns:
+// - The matching network.
+// - An error if no network or more than one network matches the filter or if any API request fails.
+func FindNetwork(ctx context.Context, client *vstack_api.Client, filter NetworkFilter) (vstack_api.Network, error) {
+	if filter == (NetworkFilter{}) {
+		return vstack_api.Network{}, fmt.Errorf("FindNetwork: one of id, name or vdc_id must be set")
+	}
+
+	// 1. Retrieve the network directly if its ID is known.
+	if filter.ID != 0 {
+		resp, err := client.NetworkGet(ctx, vstack_api.NetworkGetParams{ID: filter.ID})
+		if err != nil {
+			return vstack_api.Network{}, fmt.Errorf("FindNetwork: %w", err)
+		}
+		if !filter.matches(resp.Data) {
+			return vstack_api.Network{}, fmt.Errorf("FindNetwork: no network matches %s", filter)
+		}
+		return resp.Data, nil
+	}
+
+	// 2. Otherwise search the networks of the VDC, or of all VDCs.
+	resp, err := client.NetworksList(ctx, vstack_api.NetworksListParams{VdcID: filter.VdcID})
+	if err != nil {
+		return vstack_api.Network{}, fmt.Errorf("FindNetwork: %w", err)
+	}
+	var found []vstack_api.Network
+	for _, network := range resp.Data {
+		if filter.matches(network) {
+			found = append(found, network)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return vstack_api.Network{}, fmt.Errorf("FindNetwork: no network matches %s", filter)
+	case 1:
+		return found[0], nil
+	default:
+		ids := make([]string, len(found))
+		for i, network := range found {
+			ids[i] = fmt.Sprintf("%d", network.ID)
+		}
+		return vstack_api.Network{}, fmt.Errorf("FindNetwork: %d networks match %s (ids %s); set a more specific filter",
+			len(found), filter, strings.Join(ids, ", "))
+	}
+}
+
+// MapNetworkDHCP converts the DHCP settings of a network to the Terraform model.
+// Disabled DHCP is mapped to nil unless the prior model is set, so an omitted dhcp block stays omitted.
+func MapNetworkDHCP(dhcp vstack_api.NetworkDHCP, prior *models.NetworkDHCPModel) *models.NetworkDHCPModel {
+	if !dhcp.Enabled && prior == nil {
+		return nil
+	}
+
+	model := &models.NetworkDHCPModel{
+		Enabled:    types.BoolValue(dhcp.Enabled),
+		RangeStart: types.StringNull(),
+		RangeEnd:   types.StringNull(),
+	}
+	if dhcp.RangeStart != "" {
+		model.RangeStart = types.StringValue(dhcp.RangeStart)
+	}
+	if dhcp.RangeEnd != "" {
+		model.RangeEnd = types.StringValue(dhcp.RangeEnd)
+	}
+	for _, server := range dhcp.DNSServers {
+		model.DNSServers = append(model.DNSServers, types.StringValue(server))
+	}
+	return model
+}
+
+// NetworkDHCPParams converts the DHCP settings of the Terraform model to the API parameters.
+// A nil model disables DHCP.
+func NetworkDHCPParams(model *models.NetworkDHCPModel) vstack_api.NetworkDHCP {
+	if model == nil {
+		return vstack_api.NetworkDHCP{}
+	}
+
+	dhcp := vstack_api.NetworkDHCP{
+		Enabled:    model.Enabled.ValueBool(),
+		RangeStart: model.RangeStart.ValueString(),
+		RangeEnd:   model.RangeEnd.ValueString(),
+	}
+	for _, server := range model.DNSServers {
+		dhcp.DNSServers = append(dhcp.DNSServers, server.ValueString())
+	}
+	return dhcp
+}
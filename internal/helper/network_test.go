@@ -0,0 +1,55 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/vstack_api"
+	"terraform-provider-vstack/internal/vstacktest"
+)
+
+func TestFindNetwork(t *testing.T) {
+	server, client, _ := newFakeVM(t, "")
+	ctx := context.Background()
+
+	created, err := client.NetworksCreate(ctx, vstack_api.NetworksCreateParams{
+		VdcID: vstacktest.DefaultVdcID,
+		Name:  "backend",
+		Type:  vstack_api.NetworkTypeVXLAN,
+		CIDR:  "192.168.10.0/24",
+		DHCP:  vstack_api.NetworkDHCP{Enabled: true, RangeStart: "192.168.10.100", RangeEnd: "192.168.10.200"},
+	})
+	if err != nil {
+		t.Fatalf("NetworksCreate: %v", err)
+	}
+	server.AddNetwork(vstack_api.Network{Name: "backend", VdcID: vstacktest.DefaultVdcID + 1, Type: vstack_api.NetworkTypeVLAN, Tag: 7})
+
+	// A name shared by networks of different VDCs is resolved with the VDC.
+	network, err := helper.FindNetwork(ctx, client, helper.NetworkFilter{Name: "backend", VdcID: vstacktest.DefaultVdcID})
+	if err != nil {
+		t.Fatalf("FindNetwork: %v", err)
+	}
+	if network.ID != created.Data.ID || !network.DHCP.Enabled || network.Tag == 0 {
+		t.Errorf("expected network %d with DHCP and an allocated tag, got %+v", created.Data.ID, network)
+	}
+
+	if _, err := helper.FindNetwork(ctx, client, helper.NetworkFilter{Name: "backend"}); err == nil || !strings.Contains(err.Error(), "2 networks match") {
+		t.Errorf("expected an ambiguous match error, got %v", err)
+	}
+	if _, err := helper.FindNetwork(ctx, client, helper.NetworkFilter{ID: created.Data.ID, Name: "frontend"}); err == nil {
+		t.Errorf("expected no match for a network ID with another name")
+	}
+
+	network, err = helper.FindNetwork(ctx, client, helper.NetworkFilter{ID: vstacktest.DefaultNetworkID})
+	if err != nil {
+		t.Fatalf("FindNetwork by ID: %v", err)
+	}
+	if network.Name != vstacktest.DefaultNetworkName {
+		t.Errorf("expected the default network, got %q", network.Name)
+	}
+}
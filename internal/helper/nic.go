@@ -4,40 +4,37 @@
 package helper
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
 
 	"terraform-provider-vstack/internal/vstack_api"
 )
 
+// ErrNicNotFound is returned by FindNicInVmGet when the VM has no NIC with the port ID.
+var ErrNicNotFound = errors.New("NIC not found")
+
 // FindNicInVmGet searches for the Network Interface Card (NIC) with the specified portID
 // within the details of a Virtual Machine (VM) obtained from the API.
 //
 // Parameters:
-// - client: An HTTP client used to make API requests.
-// - authCookie: The authentication cookie required for API access.
-// - baseURL: The base URL of the API endpoint.
+// - ctx: The context of the operation; cancelling it aborts the API call.
+// - client: The vStack API client used to make API requests.
 // - vmID: The unique identifier of the VM.
 // - portID: The unique identifier of the NIC to be searched.
 //
 // Returns:
 // - A vstack_api.NetworkPort struct representing the found NIC.
-// - An error if the NIC is not found or if any API request fails.
+// - An error wrapping ErrNicNotFound if the NIC is not found, or an error if any API request fails.
 func FindNicInVmGet(
-	client *http.Client,
-	authCookie string,
-	baseURL string,
+	ctx context.Context,
+	client *vstack_api.Client,
 	vmID int64,
 	portID int64,
 ) (vstack_api.NetworkPort, error) {
 
-	// Build the JSON-RPC payload for the "vm-get" method.
-	requestPayload := BuildJSONRPCRequest("vm-get", map[string]interface{}{
-		"id": vmID,
-	})
-
 	// Execute the "vm-get" API call to retrieve VM details.
-	vmResp, err := vstack_api.VmGet(requestPayload, authCookie, baseURL, client)
+	vmResp, err := client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
 	if err != nil {
 		return vstack_api.NetworkPort{}, fmt.Errorf("FindNicInVmGet: error calling vstack_api.VmGet: %w", err)
 	}
@@ -53,16 +50,15 @@ func FindNicInVmGet(
 	}
 
 	// If the NIC with the specified portID is not found, return an error.
-	return vstack_api.NetworkPort{}, fmt.Errorf("FindNicInVmGet: NIC with port_id=%d not found in VM (id=%d)", portID, vmID)
+	return vstack_api.NetworkPort{}, fmt.Errorf("FindNicInVmGet: port_id=%d in VM (id=%d): %w", portID, vmID, ErrNicNotFound)
 }
 
 // SetNicRatelimit updates the rate limit (in megabits) for a specific NIC within a VM.
 // It calls the "vm-ratelimit-nic" API method to apply the new rate limit.
 //
 // Parameters:
-// - client: An HTTP client used to make API requests.
-// - authCookie: The authentication cookie required for API access.
-// - baseURL: The base URL of the API endpoint.
+// - ctx: The context of the operation; cancelling it aborts the API call.
+// - client: The vStack API client used to make API requests.
 // - vmID: The unique identifier of the VM.
 // - portID: The unique identifier of the NIC whose rate limit is to be updated.
 // - ratelimitMbits: The new rate limit value in megabits.
@@ -70,22 +66,18 @@ func FindNicInVmGet(
 // Returns:
 // - An error if the API call fails or if the rate limit update is unsuccessful.
 func SetNicRatelimit(
-	client *http.Client,
-	authCookie string,
-	baseURL string,
+	ctx context.Context,
+	client *vstack_api.Client,
 	vmID int64,
 	portID int64,
 	ratelimitMbits int64,
 ) error {
-	// Build the JSON-RPC payload for the "vm-ratelimit-nic" method.
-	reqPayload := BuildJSONRPCRequest("vm-ratelimit-nic", map[string]interface{}{
-		"vm_id":           vmID,
-		"port_id":         portID,
-		"ratelimit_mbits": ratelimitMbits,
-	})
-
 	// Execute the "vm-ratelimit-nic" API call to update the NIC's rate limit.
-	_, err := vstack_api.VmRatelimitNic(reqPayload, authCookie, baseURL, client)
+	_, err := client.VmRatelimitNic(ctx, vstack_api.VmRatelimitNicParams{
+		VmID:           vmID,
+		PortID:         portID,
+		RatelimitMBits: ratelimitMbits,
+	})
 	if err != nil {
 		// API returned an error; wrap it with additional context and return.
 		return fmt.Errorf("SetNicRatelimit: error from API: %w", err)
@@ -94,3 +86,59 @@ func SetNicRatelimit(
 	// Successfully updated the NIC's rate limit.
 	return nil
 }
+
+// ReattachNic detaches a NIC and attaches it again with new settings, keeping its MAC address and slot.
+// It is used for changes vStack cannot apply to an attached port, e.g. moving it to another network.
+// If the NIC cannot be attached with the new settings, it is attached again with the old ones.
+//
+// Parameters:
+// - ctx: The context of the operation; cancelling it aborts the API calls.
+// - client: The vStack API client used to make API requests.
+// - vmID: The unique identifier of the VM.
+// - old: The NIC as returned by "vm-get".
+// - params: The settings of the NIC to attach; ID, Slot and MAC are taken from the VM and the old NIC.
+//
+// Returns:
+// - The result of "vms-add-nic" for the new port, or for the restored port together with the error; the port ID differs from the old one.
+// - An error if the NIC cannot be detached or attached with the new settings.
+//
+// Only an error of the detach wraps the *vstack_api.Error, so that a refusal of vStack after the NIC was
+// detached is not mistaken for a hot-plug refusal by ApplyHardwareChange.
+func ReattachNic(
+	ctx context.Context,
+	client *vstack_api.Client,
+	vmID int64,
+	old vstack_api.NetworkPort,
+	params vstack_api.VmsAddNicParams,
+) (vstack_api.VmsAddNicResult, error) {
+	params.ID = vmID
+	params.Slot = old.Slot
+	params.MAC = old.MAC
+
+	if _, err := client.VmRemoveNic(ctx, vstack_api.VmRemoveNicParams{VmID: vmID, PortID: old.PortID}); err != nil {
+		return vstack_api.VmsAddNicResult{}, fmt.Errorf("ReattachNic: failed to detach NIC %d: %w", old.PortID, err)
+	}
+
+	result, err := client.VmsAddNic(ctx, params)
+	if err == nil {
+		return result, nil
+	}
+	// The old port is already detached, so the change must not be retried as if it had been refused.
+	err = fmt.Errorf("ReattachNic: failed to attach NIC to network %d: %v", params.NetworkID, err)
+
+	// Restore the old NIC, so that the VM does not lose its network connection.
+	restored, restoreErr := client.VmsAddNic(ctx, vstack_api.VmsAddNicParams{
+		ID:             vmID,
+		NetworkID:      old.NetworkID,
+		Slot:           old.Slot,
+		RatelimitMBits: old.RatelimitMBits,
+		Address:        old.Address,
+		IPGuard:        old.IPGuard,
+		MAC:            old.MAC,
+	})
+	if restoreErr != nil {
+		return vstack_api.VmsAddNicResult{}, errors.Join(err,
+			fmt.Errorf("ReattachNic: failed to restore NIC in slot %d: %v", old.Slot, restoreErr))
+	}
+	return restored, err
+}
@@ -0,0 +1,86 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/vstack_api"
+	"terraform-provider-vstack/internal/vstacktest"
+)
+
+func TestReattachNicKeepsMACAndSlot(t *testing.T) {
+	_, client, vmID := newFakeVM(t, "")
+	ctx := context.Background()
+
+	added, err := client.VmsAddNic(ctx, vstack_api.VmsAddNicParams{ID: vmID, NetworkID: vstacktest.DefaultNetworkID, Slot: 2})
+	if err != nil {
+		t.Fatalf("VmsAddNic: %v", err)
+	}
+	old, err := helper.FindNicInVmGet(ctx, client, vmID, added.Data.PortID)
+	if err != nil {
+		t.Fatalf("FindNicInVmGet: %v", err)
+	}
+
+	res, err := helper.ReattachNic(ctx, client, vmID, old, vstack_api.VmsAddNicParams{
+		NetworkID: vstacktest.DefaultNetworkID + 1,
+		Address:   "10.0.0.42",
+	})
+	if err != nil {
+		t.Fatalf("ReattachNic: %v", err)
+	}
+	if res.Data.PortID == old.PortID {
+		t.Errorf("expected the re-attached NIC to get a new port ID")
+	}
+
+	nic, err := helper.FindNicInVmGet(ctx, client, vmID, res.Data.PortID)
+	if err != nil {
+		t.Fatalf("FindNicInVmGet: %v", err)
+	}
+	if nic.MAC != old.MAC || nic.Slot != old.Slot {
+		t.Errorf("expected MAC %s and slot %d to be kept, got %s and %d", old.MAC, old.Slot, nic.MAC, nic.Slot)
+	}
+	if nic.NetworkID != vstacktest.DefaultNetworkID+1 || nic.Address != "10.0.0.42" {
+		t.Errorf("expected network %d and address 10.0.0.42, got %d and %s", vstacktest.DefaultNetworkID+1, nic.NetworkID, nic.Address)
+	}
+	if _, err := helper.FindNicInVmGet(ctx, client, vmID, old.PortID); !errors.Is(err, helper.ErrNicNotFound) {
+		t.Errorf("expected the old port %d to be detached, got %v", old.PortID, err)
+	}
+}
+
+func TestReattachNicReturnsRestoredPort(t *testing.T) {
+	server, client, vmID := newFakeVM(t, "")
+	ctx := context.Background()
+
+	added, err := client.VmsAddNic(ctx, vstack_api.VmsAddNicParams{ID: vmID, NetworkID: vstacktest.DefaultNetworkID, Slot: 2})
+	if err != nil {
+		t.Fatalf("VmsAddNic: %v", err)
+	}
+	old, err := helper.FindNicInVmGet(ctx, client, vmID, added.Data.PortID)
+	if err != nil {
+		t.Fatalf("FindNicInVmGet: %v", err)
+	}
+
+	// vStack refuses the new settings after the old port is detached; the old port is restored.
+	server.InjectError("vms-add-nic", 409, "network is full", 1)
+	res, err := helper.ReattachNic(ctx, client, vmID, old, vstack_api.VmsAddNicParams{NetworkID: vstacktest.DefaultNetworkID + 1})
+	if err == nil {
+		t.Fatal("expected the re-attach to fail")
+	}
+	var apiErr *vstack_api.Error
+	if errors.As(err, &apiErr) {
+		t.Errorf("expected the refusal after the detach not to be reported as a vStack error, got %v", err)
+	}
+
+	nic, err := helper.FindNicInVmGet(ctx, client, vmID, res.Data.PortID)
+	if err != nil {
+		t.Fatalf("expected the restored port to be returned: %v", err)
+	}
+	if nic.NetworkID != old.NetworkID || nic.MAC != old.MAC || nic.Slot != old.Slot {
+		t.Errorf("expected the NIC to be restored with its old settings, got %+v", nic)
+	}
+}
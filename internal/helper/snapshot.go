@@ -0,0 +1,87 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// SnapshotImportIDFormat is the import ID format of the vstack_vm_snapshot resource.
+const SnapshotImportIDFormat = `"<vm_id>/<dataset>@<name>"`
+
+// SnapshotID returns the ID of the vstack_vm_snapshot resource, "<vm_id>/<dataset>@<name>".
+// It is also the import ID of the resource.
+func SnapshotID(vmID int64, dataset, name string) string {
+	return fmt.Sprintf("%d/%s@%s", vmID, dataset, name)
+}
+
+// ParseSnapshotImportID parses a vstack_vm_snapshot import ID in the SnapshotImportIDFormat, as returned by SnapshotID.
+// The dataset may contain slashes; the name is the part after the last "@".
+func ParseSnapshotImportID(id string) (vmID int64, dataset, name string, err error) {
+	slash := strings.Index(id, "/")
+	at := strings.LastIndex(id, "@")
+	if slash < 0 || at < slash {
+		return 0, "", "", fmt.Errorf("ParseSnapshotImportID: invalid import ID %q, expected %s", id, SnapshotImportIDFormat)
+	}
+	vmID, parseErr := strconv.ParseInt(id[:slash], 10, 64)
+	dataset, name = id[slash+1:at], id[at+1:]
+	if parseErr != nil || vmID <= 0 || dataset == "" || name == "" {
+		return 0, "", "", fmt.Errorf("ParseSnapshotImportID: invalid import ID %q, expected %s", id, SnapshotImportIDFormat)
+	}
+	return vmID, dataset, name, nil
+}
+
+// FindVMSnapshot returns the snapshot of a VM dataset with the given name.
+//
+// Parameters:
+// - ctx: The context of the operation.
+// - client: The vStack API client used to make API requests.
+// - vmID: The ID of the VM.
+// - dataset: The snapshotted dataset of the VM.
+// - name: The name of the snapshot.
+//
+// Returns:
+// - The snapshot and true, or false if the dataset has no such snapshot, e.g. because it expired.
+// - An error if the API request fails.
+func FindVMSnapshot(ctx context.Context, client *vstack_api.Client, vmID int64, dataset, name string) (vstack_api.VmSnapshot, bool, error) {
+	listResp, err := client.VmSnapshotsList(ctx, vstack_api.VmSnapshotsListParams{ID: vmID, Dataset: dataset})
+	if err != nil {
+		return vstack_api.VmSnapshot{}, false, fmt.Errorf("FindVMSnapshot: %w", err)
+	}
+	for _, snapshot := range listResp.Data {
+		if snapshot.Dataset == dataset && snapshot.Name == name {
+			return snapshot, true, nil
+		}
+	}
+	return vstack_api.VmSnapshot{}, false, nil
+}
+
+// SortVMSnapshots sorts snapshots from the oldest to the newest, then by dataset and name.
+func SortVMSnapshots(snapshots []vstack_api.VmSnapshot) {
+	sort.Slice(snapshots, func(i, j int) bool {
+		a, b := snapshots[i], snapshots[j]
+		if a.Created != b.Created {
+			return a.Created < b.Created
+		}
+		if a.Dataset != b.Dataset {
+			return a.Dataset < b.Dataset
+		}
+		return a.Name < b.Name
+	})
+}
+
+// FormatSnapshotTime formats a Unix time of a snapshot in RFC 3339, or returns an empty string for zero.
+func FormatSnapshotTime(unix int64) string {
+	if unix == 0 {
+		return ""
+	}
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+func TestFindVMSnapshot(t *testing.T) {
+	server, client, vmID := newFakeVM(t, "")
+	ctx := context.Background()
+
+	vm, err := client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
+	if err != nil {
+		t.Fatalf("VmGet: %v", err)
+	}
+	dataset := fmt.Sprintf("%v", vm.Data.RootDataset)
+	created, err := client.VmSnapshotsCreate(ctx, vstack_api.VmSnapshotsCreateParams{
+		ID: vmID, Dataset: dataset, Name: "before-upgrade", Description: "pre", Retention: 3600,
+	})
+	if err != nil {
+		t.Fatalf("VmSnapshotsCreate: %v", err)
+	}
+	if created.Data.Expires != created.Data.Created+3600 {
+		t.Errorf("expected the snapshot to expire after the retention, got created %d, expires %d", created.Data.Created, created.Data.Expires)
+	}
+
+	got, found, err := helper.FindVMSnapshot(ctx, client, vmID, dataset, "before-upgrade")
+	if err != nil || !found {
+		t.Fatalf("FindVMSnapshot: found %v, err %v", found, err)
+	}
+	if got != created.Data {
+		t.Errorf("FindVMSnapshot = %+v, want %+v", got, created.Data)
+	}
+
+	// An expired snapshot is no longer found.
+	server.ExpireSnapshot(vmID, "before-upgrade")
+	if _, found, err := helper.FindVMSnapshot(ctx, client, vmID, dataset, "before-upgrade"); err != nil || found {
+		t.Errorf("expected the expired snapshot not to be found, got found %v, err %v", found, err)
+	}
+
+	// A missing VM is reported as an API error.
+	_, _, err = helper.FindVMSnapshot(ctx, client, vmID+1, dataset, "before-upgrade")
+	var apiErr *vstack_api.Error
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected a vStack API error for a missing VM, got %v", err)
+	}
+}
+
+func TestParseSnapshotImportID(t *testing.T) {
+	// The import ID is the resource ID.
+	id := helper.SnapshotID(1234, "pool/vms/1234", "daily")
+	vmID, dataset, name, err := helper.ParseSnapshotImportID(id)
+	if err != nil || vmID != 1234 || dataset != "pool/vms/1234" || name != "daily" {
+		t.Errorf("ParseSnapshotImportID(%q) = %d, %q, %q, %v", id, vmID, dataset, name, err)
+	}
+
+	for _, id := range []string{"1234", "1234/pool", "x/pool@daily", "1234/@daily", "1234/pool@", "0/pool@daily", "pool@daily/1234"} {
+		if _, _, _, err := helper.ParseSnapshotImportID(id); err == nil {
+			t.Errorf("ParseSnapshotImportID(%q): expected an error", id)
+		}
+	}
+}
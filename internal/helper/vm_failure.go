@@ -0,0 +1,69 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import (
+	"fmt"
+
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// failedStatusRemediation maps the terminal failure statuses of a VM to the way out of them.
+// A VM in one of these statuses does not leave it without an operator or Terraform intervening.
+var failedStatusRemediation = map[int64]string{
+	Status.CreateFailed: "The VM could not be created and has to be recreated, " +
+		"e.g. with `terraform apply -replace` or by setting recreate_on_failure.",
+	Status.StartFailed: "The VM could not be started. Check the hypervisor faults, boot media and disks of the VM, " +
+		"then apply again to retry the start, or recreate the VM.",
+	Status.StopFailed: "The VM could not be stopped. Apply again to retry the stop, " +
+		"or power the VM off in vStack before applying again.",
+	Status.DeleteFailed: "The VM could not be removed. Apply again to retry the removal, " +
+		"or remove the VM in vStack and run `terraform state rm`.",
+}
+
+// IsFailedStatus reports whether an operational status is a terminal failure status,
+// i.e. CreateFailed, StartFailed, StopFailed or DeleteFailed.
+func IsFailedStatus(status int64) bool {
+	_, ok := failedStatusRemediation[status]
+	return ok
+}
+
+// VMFailedError is returned when a VM is in a terminal failure status.
+type VMFailedError struct {
+	VmID     int64               // The unique identifier of the VM.
+	Status   int64               // The failure status of the VM.
+	HVFaults vstack_api.HVFaults // The hypervisor faults reported for the VM.
+}
+
+// Error implements the error interface for the VMFailedError struct.
+func (e *VMFailedError) Error() string {
+	return fmt.Sprintf("VM %d is in failure status %s; %s",
+		e.VmID, StatusName(e.Status), FormatHVFaults(e.HVFaults))
+}
+
+// FailureRemediation returns how to recover a VM from a terminal failure status,
+// or an empty string if the status is not one.
+func FailureRemediation(status int64) string {
+	return failedStatusRemediation[status]
+}
+
+// CheckVMFailure checks whether the VM of a "vm-get" response is in a terminal failure status.
+//
+// Parameters:
+// - resp: The "vm-get" response of the VM.
+//
+// Returns:
+// - A *VMFailedError if the VM is in a terminal failure status, nil otherwise.
+func CheckVMFailure(resp vstack_api.VmGetResult) error {
+	if !IsFailedStatus(resp.Data.OperStatus) {
+		return nil
+	}
+	return &VMFailedError{VmID: resp.Data.ID, Status: resp.Data.OperStatus, HVFaults: resp.Data.HVFaults}
+}
+
+// FormatHVFaults returns a human-readable description of the hypervisor faults of a VM.
+func FormatHVFaults(faults vstack_api.HVFaults) string {
+	return fmt.Sprintf("hv_faults: sigabort interval=%d restarts=%d",
+		faults.Sigabort.Interval, faults.Sigabort.Restarts)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// VMFilter selects VMs of a "vms-list" result. Zero values and nil fields match any VM.
+type VMFilter struct {
+	VdcID      int64
+	Node       int64
+	Pool       string
+	NameRegex  *regexp.Regexp // Matched against the VM name; unanchored like regexp.MatchString.
+	OperStatus *int64
+	Tag        string // The VM must carry this tag.
+}
+
+// matches reports whether the VM matches the filter.
+func (f VMFilter) matches(vm vstack_api.VmListItem) bool {
+	if (f.VdcID != 0 && vm.Vdc != f.VdcID) || (f.Node != 0 && vm.Node != f.Node) || (f.Pool != "" && vm.Pool != f.Pool) {
+		return false
+	}
+	if f.NameRegex != nil && !f.NameRegex.MatchString(vm.Name) {
+		return false
+	}
+	if f.OperStatus != nil && vm.OperStatus != *f.OperStatus {
+		return false
+	}
+	if f.Tag != "" {
+		for _, tag := range vm.Tags {
+			if tag == f.Tag {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// ListVMs returns the full details of the VMs matching the filter, in ascending order of their IDs.
+// The VDC, node and pool are passed to "vms-list"; the other criteria are applied to its result,
+// and every matching VM is then retrieved with "vm-get".
+//
+// Parameters:
+// - ctx: The context of the operation.
+// - client: The vStack API client used to make API requests.
+// - filter: The criteria the VMs must match.
+//
+// Returns:
+// - The "vm-get" results of the matching VMs. VMs removed while they are listed are skipped.
+// - An error if any API request fails.
+func ListVMs(ctx context.Context, client *vstack_api.Client, filter VMFilter) ([]vstack_api.VmGetResult, error) {
+	// 1. List the VMs, letting vStack filter by VDC, node and pool.
+	listResp, err := client.VmsList(ctx, vstack_api.VmsListParams{VdcID: filter.VdcID, Node: filter.Node, Pool: filter.Pool})
+	if err != nil {
+		return nil, fmt.Errorf("ListVMs: %w", err)
+	}
+
+	var ids []int64
+	for _, vm := range listResp.Data {
+		if filter.matches(vm) {
+			ids = append(ids, vm.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	// 2. Retrieve the details of the matching VMs.
+	result := make([]vstack_api.VmGetResult, 0, len(ids))
+	for _, id := range ids {
+		getResp, err := client.VmGet(ctx, vstack_api.VmGetParams{ID: id})
+		if err != nil {
+			// A VM that is not found was removed after it was listed.
+			if vstack_api.IsNotFound(err) {
+				tflog.Warn(ctx, "Skipping VM removed while listing VMs", map[string]any{"vm_id": id, "error": err.Error()})
+				continue
+			}
+			return nil, fmt.Errorf("ListVMs: %w", err)
+		}
+		result = append(result, getResp)
+	}
+
+	return result, nil
+}
+
+// FindVMByName returns the ID of the only VM with the given name, optionally within a VDC.
+//
+// Parameters:
+// - ctx: The context of the operation.
+// - client: The vStack API client used to make API requests.
+// - name: The exact name of the VM.
+// - vdcID: The VDC of the VM; zero searches all VDCs.
+//
+// Returns:
+// - The ID of the matching VM.
+// - An error if no VM or more than one VM has the name or if the API request fails.
+func FindVMByName(ctx context.Context, client *vstack_api.Client, name string, vdcID int64) (int64, error) {
+	listResp, err := client.VmsList(ctx, vstack_api.VmsListParams{VdcID: vdcID})
+	if err != nil {
+		return 0, fmt.Errorf("FindVMByName: %w", err)
+	}
+
+	var ids []int64
+	for _, vm := range listResp.Data {
+		if vm.Name == name && (vdcID == 0 || vm.Vdc == vdcID) {
+			ids = append(ids, vm.ID)
+		}
+	}
+
+	where := ""
+	if vdcID != 0 {
+		where = fmt.Sprintf(" in VDC %d", vdcID)
+	}
+	switch len(ids) {
+	case 0:
+		return 0, fmt.Errorf("FindVMByName: no VM named %q%s", name, where)
+	case 1:
+		return ids[0], nil
+	default:
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		idList := make([]string, len(ids))
+		for i, id := range ids {
+			idList[i] = fmt.Sprintf("%d", id)
+		}
+		return 0, fmt.Errorf("FindVMByName: %d VMs named %q%s (ids %s); set vdc_id or use id",
+			len(ids), name, where, strings.Join(idList, ", "))
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper_test
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+func TestListVMsFilters(t *testing.T) {
+	server, client, first := newFakeVM(t, "")
+	ctx := context.Background()
+
+	var ids []int64
+	for _, name := range []string{"web-1", "web-2", "db-1"} {
+		vm, err := client.VmCreate(ctx, vstack_api.VmCreateParams{Name: name, CPUs: 1, RAM: 1 << 30, OsProfile: "4001"})
+		if err != nil {
+			t.Fatalf("VmCreate: %v", err)
+		}
+		ids = append(ids, vm.Data.ID)
+	}
+	server.SetTags(ids[1], "prod")
+	server.SetTags(ids[2], "prod")
+	if err := helper.PerformAction(ctx, client, ids[1], "start", 0); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	started := helper.Status.Started
+	tests := []struct {
+		name   string
+		filter helper.VMFilter
+		want   []int64
+	}{
+		{"all", helper.VMFilter{}, []int64{first, ids[0], ids[1], ids[2]}},
+		{"name regex", helper.VMFilter{NameRegex: regexp.MustCompile(`^web-`)}, []int64{ids[0], ids[1]}},
+		{"tag", helper.VMFilter{Tag: "prod"}, []int64{ids[1], ids[2]}},
+		{"oper status and tag", helper.VMFilter{OperStatus: &started, Tag: "prod"}, []int64{ids[1]}},
+		{"no match", helper.VMFilter{Pool: "other"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vms, err := helper.ListVMs(ctx, client, tt.filter)
+			if err != nil {
+				t.Fatalf("ListVMs: %v", err)
+			}
+			var got []int64
+			for _, vm := range vms {
+				got = append(got, vm.Data.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected VMs %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected VMs %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestListVMsSkipsOnlyRemovedVMs(t *testing.T) {
+	server, client, vmID := newFakeVM(t, "")
+	ctx := context.Background()
+
+	// A VM that is not found was removed after it was listed.
+	server.InjectError("vm-get", 404, "VM not found", 1)
+	vms, err := helper.ListVMs(ctx, client, helper.VMFilter{})
+	if err != nil || len(vms) != 0 {
+		t.Fatalf("expected the removed VM to be skipped, got %d VMs, %v", len(vms), err)
+	}
+
+	// Any other error would leave out a VM that still exists.
+	server.InjectError("vm-get", 409, "VM is locked", 1)
+	if _, err := helper.ListVMs(ctx, client, helper.VMFilter{}); err == nil {
+		t.Errorf("expected ListVMs to fail instead of skipping VM %d", vmID)
+	}
+}
+
+func TestFindVMByName(t *testing.T) {
+	_, client, vmID := newFakeVM(t, "")
+	ctx := context.Background()
+
+	id, err := helper.FindVMByName(ctx, client, "vm", 0)
+	if err != nil || id != vmID {
+		t.Fatalf("expected VM %d, got %d (err %v)", vmID, id, err)
+	}
+
+	if _, err := client.VmCreate(ctx, vstack_api.VmCreateParams{Name: "vm", CPUs: 1, RAM: 1 << 30, OsProfile: "4001", VdcID: 2}); err != nil {
+		t.Fatalf("VmCreate: %v", err)
+	}
+	if _, err := helper.FindVMByName(ctx, client, "vm", 0); err == nil || !strings.Contains(err.Error(), "2 VMs named") {
+		t.Errorf("expected an ambiguous name error, got %v", err)
+	}
+	if id, err := helper.FindVMByName(ctx, client, "vm", 2); err != nil || id == vmID {
+		t.Errorf("expected the VM of VDC 2, got %d (err %v)", id, err)
+	}
+	if _, err := helper.FindVMByName(ctx, client, "missing", 0); err == nil || !strings.Contains(err.Error(), "no VM named") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
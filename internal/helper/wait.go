@@ -0,0 +1,164 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// DefaultStatusTimeout is the default time to wait for a VM to reach the operational status requested by an action.
+const DefaultStatusTimeout = 10 * time.Minute
+
+// StatusPollInterval is the interval between two "vm-get" calls while waiting for a VM status.
+var StatusPollInterval = 5 * time.Second
+
+// failureStatuses maps a target operational status to the statuses that mean it will never be reached.
+var failureStatuses = map[int64][]int64{
+	Status.Started:   {Status.StartFailed, Status.ResumeFailed, Status.CreateFailed},
+	Status.Offline:   {Status.StopFailed, Status.CreateFailed},
+	Status.Suspended: {Status.SuspendFailed},
+}
+
+// StatusTimeoutError is returned when a VM does not reach the expected operational status, or node, in time.
+type StatusTimeoutError struct {
+	VmID       int64         // The unique identifier of the VM.
+	Target     int64         // The operational status the VM was expected to reach.
+	Node       int64         // The node the VM was expected to be migrated to; zero if not waiting for a migration.
+	LastStatus int64         // The last operational status observed.
+	LastNode   int64         // The last node the VM was observed on.
+	Timeout    time.Duration // The time waited for the status.
+}
+
+// Error implements the error interface for the StatusTimeoutError struct.
+func (e *StatusTimeoutError) Error() string {
+	if e.Node != 0 {
+		return fmt.Sprintf("VM %d was not migrated to node %d within %s; it is still reported on node %d with status %s",
+			e.VmID, e.Node, e.Timeout.Round(time.Millisecond), e.LastNode, StatusName(e.LastStatus))
+	}
+	return fmt.Sprintf("VM %d did not reach status %s within %s; last observed status is %s",
+		e.VmID, StatusName(e.Target), e.Timeout.Round(time.Millisecond), StatusName(e.LastStatus))
+}
+
+// StatusFailedError is returned when a VM enters a failure status while waiting for another status.
+type StatusFailedError struct {
+	VmID     int64               // The unique identifier of the VM.
+	Target   int64               // The operational status the VM was expected to reach.
+	Status   int64               // The failure status the VM has entered.
+	HVFaults vstack_api.HVFaults // The hypervisor faults reported for the VM.
+}
+
+// Error implements the error interface for the StatusFailedError struct.
+func (e *StatusFailedError) Error() string {
+	return fmt.Sprintf("VM %d entered status %s while waiting for status %s; %s",
+		e.VmID, StatusName(e.Status), StatusName(e.Target), FormatHVFaults(e.HVFaults))
+}
+
+// WaitForOperStatus polls "vm-get" until the VM reaches the target operational status.
+// It fails when the VM enters a failure status for the target (e.g. StartFailed while waiting for Started),
+// when the timeout expires or when ctx is done.
+//
+// Parameters:
+// - ctx: The context of the operation; cancelling it stops waiting.
+// - client: The vStack API client used to make API requests.
+// - vmID: The unique identifier of the VM.
+// - target: The operational status to wait for, e.g. Status.Started.
+// - timeout: The maximum time to wait; DefaultStatusTimeout is used if it is not positive, an earlier ctx deadline shortens it.
+//
+// Returns:
+// - The last "vm-get" response, which reflects the target status on success.
+// - An error if the status is not reached.
+func WaitForOperStatus(
+	ctx context.Context,
+	client *vstack_api.Client,
+	vmID int64,
+	target int64,
+	timeout time.Duration,
+) (vstack_api.VmGetResult, error) {
+	expected := StatusTimeoutError{VmID: vmID, Target: target}
+	return pollVM(ctx, client, expected, timeout, func(resp vstack_api.VmGetResult) (bool, error) {
+		status := resp.Data.OperStatus
+		if status == target {
+			return true, nil
+		}
+		for _, failed := range failureStatuses[target] {
+			if status == failed {
+				return false, &StatusFailedError{VmID: vmID, Target: target, Status: status, HVFaults: resp.Data.HVFaults}
+			}
+		}
+		return false, nil
+	})
+}
+
+// pollVM polls "vm-get" every StatusPollInterval until done reports that the VM is in the expected state.
+//
+// Parameters:
+// - ctx: The context of the operation; cancelling it stops waiting.
+// - client: The vStack API client used to make API requests.
+// - expected: The VM and the status or node waited for, returned with the last observations on timeout.
+// - timeout: The maximum time to wait; DefaultStatusTimeout is used if it is not positive, an earlier ctx deadline shortens it.
+// - done: Reports whether a "vm-get" response is in the expected state, or an error to stop waiting with.
+//
+// Returns:
+// - The last "vm-get" response.
+// - The error of done, a *StatusTimeoutError if the timeout expires, or an error if the API call fails or ctx is done.
+func pollVM(
+	ctx context.Context,
+	client *vstack_api.Client,
+	expected StatusTimeoutError,
+	timeout time.Duration,
+	done func(resp vstack_api.VmGetResult) (bool, error),
+) (vstack_api.VmGetResult, error) {
+	if timeout <= 0 {
+		timeout = DefaultStatusTimeout
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	timeoutErr := func(resp vstack_api.VmGetResult) error {
+		err := expected
+		err.LastStatus = resp.Data.OperStatus
+		err.LastNode = resp.Data.Node
+		err.Timeout = timeout
+		return &err
+	}
+
+	var lastResp vstack_api.VmGetResult
+	for {
+		resp, err := client.VmGet(waitCtx, vstack_api.VmGetParams{ID: expected.VmID})
+		if err != nil {
+			if !errors.Is(ctx.Err(), context.Canceled) && errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+				return lastResp, timeoutErr(lastResp)
+			}
+			return lastResp, fmt.Errorf("pollVM: failed to get VM status: %w", err)
+		}
+		lastResp = resp
+
+		finished, err := done(resp)
+		if finished || err != nil {
+			return resp, err
+		}
+
+		timer := time.NewTimer(StatusPollInterval)
+		select {
+		case <-waitCtx.Done():
+			timer.Stop()
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return resp, fmt.Errorf("pollVM: %w", ctx.Err())
+			}
+			return resp, timeoutErr(resp)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// newStatusTestClient starts a server that answers "vm-get" with the given operational statuses in turn,
+// repeating the last one, and returns a client for it.
+func newStatusTestClient(t *testing.T, statuses ...int64) *vstack_api.Client {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req vstack_api.BaseJSONRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		i := int(atomic.AddInt32(&calls, 1)) - 1
+		if i >= len(statuses) {
+			i = len(statuses) - 1
+		}
+		fmt.Fprintf(w, `{"id":%q,"jsonrpc":"2.0","result":{"code":1,"data":{"id":42,"oper_status":%d,`+
+			`"hv_faults":{"sigabort":{"interval":60,"restarts":3}}}}}`, req.ID, statuses[i])
+	}))
+	t.Cleanup(server.Close)
+
+	interval := StatusPollInterval
+	StatusPollInterval = time.Millisecond
+	t.Cleanup(func() { StatusPollInterval = interval })
+
+	return vstack_api.NewClient(server.URL, server.Client())
+}
+
+func TestWaitForOperStatusReachesTarget(t *testing.T) {
+	client := newStatusTestClient(t, Status.Stopping, Status.Stopping, Status.Offline)
+
+	resp, err := WaitForOperStatus(context.Background(), client, 42, Status.Offline, time.Minute)
+	if err != nil {
+		t.Fatalf("WaitForOperStatus: %v", err)
+	}
+	if resp.Data.OperStatus != Status.Offline {
+		t.Errorf("unexpected status: %d", resp.Data.OperStatus)
+	}
+}
+
+func TestWaitForOperStatusFailsOnFailureStatus(t *testing.T) {
+	client := newStatusTestClient(t, Status.Starting, Status.StartFailed)
+
+	_, err := WaitForOperStatus(context.Background(), client, 42, Status.Started, time.Minute)
+	var failedErr *StatusFailedError
+	if !errors.As(err, &failedErr) {
+		t.Fatalf("expected a StatusFailedError, got %v", err)
+	}
+	if failedErr.Status != Status.StartFailed {
+		t.Errorf("unexpected status: %d", failedErr.Status)
+	}
+	if failedErr.HVFaults.Sigabort.Restarts != 3 {
+		t.Errorf("expected the hypervisor faults of the VM, got %+v", failedErr.HVFaults)
+	}
+}
+
+func TestWaitForOperStatusTimesOut(t *testing.T) {
+	client := newStatusTestClient(t, Status.Stopping)
+
+	_, err := WaitForOperStatus(context.Background(), client, 42, Status.Offline, 20*time.Millisecond)
+	var timeoutErr *StatusTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a StatusTimeoutError, got %v", err)
+	}
+	if timeoutErr.LastStatus != Status.Stopping {
+		t.Errorf("unexpected last status: %d", timeoutErr.LastStatus)
+	}
+}
+
+func TestCheckVMFailure(t *testing.T) {
+	resp := vstack_api.VmGetResult{}
+	resp.Data.ID = 42
+	resp.Data.OperStatus = Status.StartFailed
+	resp.Data.HVFaults.Sigabort.Restarts = 3
+
+	var failedErr *VMFailedError
+	if err := CheckVMFailure(resp); !errors.As(err, &failedErr) {
+		t.Fatalf("expected a VMFailedError, got %v", err)
+	}
+	if failedErr.Status != Status.StartFailed || failedErr.HVFaults.Sigabort.Restarts != 3 {
+		t.Errorf("unexpected failure: %+v", failedErr)
+	}
+	if FailureRemediation(failedErr.Status) == "" {
+		t.Error("expected a remediation for StartFailed")
+	}
+
+	// A VM that has been started again is no longer failed, whatever its hypervisor faults.
+	resp.Data.OperStatus = Status.Started
+	if err := CheckVMFailure(resp); err != nil {
+		t.Errorf("expected the started VM not to be failed, got %v", err)
+	}
+	if FailureRemediation(Status.Started) != "" {
+		t.Error("expected no remediation for Started")
+	}
+}
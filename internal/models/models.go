@@ -9,6 +9,50 @@ import (
 
 // VMResourceModel represents the schema for a Virtual Machine resource in Terraform.
 type VMResourceModel struct {
+	ID                   types.Int64    `tfsdk:"id"`                      // Unique identifier for the VM.
+	Name                 types.String   `tfsdk:"name"`                    // Name of the VM.
+	Description          types.String   `tfsdk:"description"`             // Description of the VM.
+	CPUs                 types.Int64    `tfsdk:"cpus"`                    // Number of CPUs allocated to the VM.
+	RAM                  types.Int64    `tfsdk:"ram"`                     // Amount of RAM (in MB) allocated to the VM.
+	CPUPriority          types.Int64    `tfsdk:"cpu_priority"`            // Priority level for CPU allocation.
+	BootMedia            types.Int64    `tfsdk:"boot_media"`              // ID of the boot media attached to the VM.
+	VcpuClass            types.Int64    `tfsdk:"vcpu_class"`              // Virtual CPU class/type.
+	OsType               types.Int64    `tfsdk:"os_type"`                 // Operating system type identifier.
+	OsProfile            types.String   `tfsdk:"os_profile"`              // Profile/configuration for the OS.
+	VdcID                types.Int64    `tfsdk:"vdc_id"`                  // Identifier for the Virtual Data Center.
+	AdminStatus          types.Int64    `tfsdk:"admin_status"`            // Administrative status of the VM.
+	Node                 types.Int64    `tfsdk:"node"`                    // Node identifier where the VM is hosted.
+	Uefi                 types.String   `tfsdk:"uefi"`                    // UEFI configuration or status.
+	CreateCompleted      types.Int64    `tfsdk:"create_completed"`        // Flag indicating if VM creation is completed.
+	Locked               types.Int64    `tfsdk:"locked"`                  // Flag indicating if the VM is locked.
+	RootDataset          types.String   `tfsdk:"root_dataset"`            // Root dataset associated with the VM.
+	RootDatasetName      types.String   `tfsdk:"root_dataset_name"`       // Name of the root dataset.
+	PoolSelector         types.String   `tfsdk:"pool_selector"`           // Selector for resource pool allocation.
+	Status               types.Int64    `tfsdk:"status"`                  // Current status of the VM.
+	OperStatus           types.Int64    `tfsdk:"oper_status"`             // Operational status of the VM.
+	Action               types.String   `tfsdk:"action"`                  // Action to be performed on the VM (start, stop, shutdown, poweroff or suspend).
+	ShutdownTimeout      types.String   `tfsdk:"shutdown_timeout"`        // Time the guest is given to shut down before the VM is powered off.
+	RebootTrigger        types.String   `tfsdk:"reboot_trigger"`          // Arbitrary value; changing it reboots the VM.
+	RecreateOnFailure    types.Bool     `tfsdk:"recreate_on_failure"`     // Replace the VM when it is found in a terminal failure status.
+	AllowRebootForUpdate types.Bool     `tfsdk:"allow_reboot_for_update"` // Allow stopping and starting the VM for changes that need it.
+	MigrateOnUpdate      types.Bool     `tfsdk:"migrate_on_update"`       // Migrate the running VM live when its node changes.
+	Guest                *GuestModel    `tfsdk:"guest"`                   // Guest OS configuration and settings.
+	Disks                []DiskModel    `tfsdk:"disks"`                   // List of disks attached to the VM.
+	Nics                 types.List     `tfsdk:"nics"`                    // NICs of the VM, managed by vstack_nic resources.
+	Source               *VMSourceModel `tfsdk:"source"`                  // VM or snapshot the VM is cloned from.
+	Timeouts             types.Object   `tfsdk:"timeouts"`                // Timeouts of the resource operations.
+}
+
+// VMSourceModel describes the VM or the snapshot a VM is cloned from.
+type VMSourceModel struct {
+	VmID       types.Int64  `tfsdk:"vm_id"`       // ID of the VM to clone.
+	SnapshotID types.String `tfsdk:"snapshot_id"` // ID of the vstack_vm_snapshot to clone.
+	Linked     types.Bool   `tfsdk:"linked"`      // Whether the clone shares the unchanged data with the source.
+}
+
+// VMDataSourceModel describes a virtual machine in the vstack_vm_get and vstack_vms data sources.
+// It has the attributes of VMResourceModel without the resource timeouts.
+type VMDataSourceModel struct {
 	ID              types.Int64  `tfsdk:"id"`                // Unique identifier for the VM.
 	Name            types.String `tfsdk:"name"`              // Name of the VM.
 	Description     types.String `tfsdk:"description"`       // Description of the VM.
@@ -30,9 +74,22 @@ type VMResourceModel struct {
 	PoolSelector    types.String `tfsdk:"pool_selector"`     // Selector for resource pool allocation.
 	Status          types.Int64  `tfsdk:"status"`            // Current status of the VM.
 	OperStatus      types.Int64  `tfsdk:"oper_status"`       // Operational status of the VM.
-	Action          types.String `tfsdk:"action"`            // Action to be performed on the VM (e.g., start, stop).
+	Action          types.String `tfsdk:"action"`            // Action matching the operational status (start, stop or suspend).
 	Guest           *GuestModel  `tfsdk:"guest"`             // Guest OS configuration and settings.
 	Disks           []DiskModel  `tfsdk:"disks"`             // List of disks attached to the VM.
+	Nics            types.List   `tfsdk:"nics"`              // NICs of the VM.
+}
+
+// VMsDataSourceModel describes the filters and the result of the vstack_vms data source.
+type VMsDataSourceModel struct {
+	VdcID      types.Int64         `tfsdk:"vdc_id"`      // Only list VMs of this VDC.
+	Node       types.Int64         `tfsdk:"node"`        // Only list VMs on this node.
+	Pool       types.String        `tfsdk:"pool"`        // Only list VMs in this pool.
+	NameRegex  types.String        `tfsdk:"name_regex"`  // Only list VMs whose name matches this regular expression.
+	OperStatus types.Int64         `tfsdk:"oper_status"` // Only list VMs with this operational status.
+	Tag        types.String        `tfsdk:"tag"`         // Only list VMs carrying this tag.
+	IDs        []types.Int64       `tfsdk:"ids"`         // IDs of the matching VMs.
+	VMs        []VMDataSourceModel `tfsdk:"vms"`         // Details of the matching VMs.
 }
 
 // DiskModel describes a disk attached to the virtual machine.
@@ -62,6 +119,9 @@ type NetworkPortModel struct {
 	IpGuard        types.Int64  `tfsdk:"ip_guard"`        // IP guard configuration/status.
 	Slot           types.Int64  `tfsdk:"slot"`            // Slot number where the network port is attached.
 	RatelimitMbits types.Int64  `tfsdk:"ratelimit_mbits"` // Rate limit (in Mbits) for the network port.
+	Hotplug        types.Bool   `tfsdk:"hotplug"`         // Whether to attach and detach the port without stopping the VM.
+	RestartPolicy  types.String `tfsdk:"restart_policy"`  // Whether the VM may be stopped to attach or detach the port.
+	Timeouts       types.Object `tfsdk:"timeouts"`        // Timeouts of the resource operations.
 }
 
 // ResolverModel configures DNS resolver settings within the guest OS.
@@ -109,3 +169,69 @@ type ProfileModel struct {
 	Description string `tfsdk:"description"` // Description os_profile, ex. Ubuntu 20.04.6 v2
 	MinSize     int64  `tfsdk:"min_size"`    // MinSize in bytes os_profile, ex. 3670016000
 }
+
+// NetworkModel represents the schema for a vStack network resource in Terraform.
+type NetworkModel struct {
+	ID          types.Int64       `tfsdk:"id"`          // Unique identifier of the network.
+	Name        types.String      `tfsdk:"name"`        // Name of the network.
+	Description types.String      `tfsdk:"description"` // Description of the network.
+	VdcID       types.Int64       `tfsdk:"vdc_id"`      // Identifier of the VDC owning the network.
+	Type        types.String      `tfsdk:"type"`        // Network type: vlan or vxlan.
+	Tag         types.Int64       `tfsdk:"tag"`         // VLAN ID or VXLAN network identifier.
+	CIDR        types.String      `tfsdk:"cidr"`        // Subnet of the network.
+	Gateway     types.String      `tfsdk:"gateway"`     // Default gateway address.
+	DHCP        *NetworkDHCPModel `tfsdk:"dhcp"`        // DHCP settings of the network.
+	Timeouts    types.Object      `tfsdk:"timeouts"`    // Timeouts of the resource operations.
+}
+
+// NetworkDataSourceModel describes a network looked up by the vstack_network data source.
+type NetworkDataSourceModel struct {
+	ID          types.Int64       `tfsdk:"id"`          // Unique identifier of the network.
+	Name        types.String      `tfsdk:"name"`        // Name of the network.
+	Description types.String      `tfsdk:"description"` // Description of the network.
+	VdcID       types.Int64       `tfsdk:"vdc_id"`      // Identifier of the VDC owning the network.
+	Type        types.String      `tfsdk:"type"`        // Network type: vlan or vxlan.
+	Tag         types.Int64       `tfsdk:"tag"`         // VLAN ID or VXLAN network identifier.
+	CIDR        types.String      `tfsdk:"cidr"`        // Subnet of the network.
+	Gateway     types.String      `tfsdk:"gateway"`     // Default gateway address.
+	DHCP        *NetworkDHCPModel `tfsdk:"dhcp"`        // DHCP settings of the network.
+}
+
+// NetworkDHCPModel describes the DHCP settings of a network.
+type NetworkDHCPModel struct {
+	Enabled    types.Bool     `tfsdk:"enabled"`     // Whether DHCP is enabled.
+	RangeStart types.String   `tfsdk:"range_start"` // First address leased by DHCP.
+	RangeEnd   types.String   `tfsdk:"range_end"`   // Last address leased by DHCP.
+	DNSServers []types.String `tfsdk:"dns_servers"` // DNS servers announced by DHCP.
+}
+
+// VMSnapshotModel represents the schema for a vStack VM snapshot resource in Terraform.
+type VMSnapshotModel struct {
+	ID          types.String `tfsdk:"id"`           // "<vm_id>/<root_dataset>@<name>".
+	VmID        types.Int64  `tfsdk:"vm_id"`        // Identifier of the snapshotted VM.
+	RootDataset types.String `tfsdk:"root_dataset"` // Snapshotted dataset of the VM.
+	Name        types.String `tfsdk:"name"`         // Name of the snapshot within the dataset.
+	Description types.String `tfsdk:"description"`  // Description of the snapshot.
+	Retention   types.String `tfsdk:"retention"`    // Go duration after which vStack removes the snapshot.
+	CreatedAt   types.String `tfsdk:"created_at"`   // RFC 3339 time the snapshot was taken at.
+	ExpiresAt   types.String `tfsdk:"expires_at"`   // RFC 3339 time vStack removes the snapshot at, or empty.
+	Timeouts    types.Object `tfsdk:"timeouts"`     // Timeouts of the resource operations.
+}
+
+// VMSnapshotsDataSourceModel describes the snapshots of a VM listed by the vstack_vm_snapshots data source.
+type VMSnapshotsDataSourceModel struct {
+	VmID        types.Int64               `tfsdk:"vm_id"`        // Identifier of the VM.
+	RootDataset types.String              `tfsdk:"root_dataset"` // Only list the snapshots of this dataset.
+	Snapshots   []VMSnapshotListItemModel `tfsdk:"snapshots"`    // Snapshots from the oldest to the newest.
+}
+
+// VMSnapshotListItemModel describes a snapshot listed by the vstack_vm_snapshots data source.
+type VMSnapshotListItemModel struct {
+	ID          types.String `tfsdk:"id"`           // "<vm_id>/<root_dataset>@<name>".
+	RootDataset types.String `tfsdk:"root_dataset"` // Snapshotted dataset of the VM.
+	Name        types.String `tfsdk:"name"`         // Name of the snapshot within the dataset.
+	Description types.String `tfsdk:"description"`  // Description of the snapshot.
+	CreatedAt   types.String `tfsdk:"created_at"`   // RFC 3339 time the snapshot was taken at.
+	ExpiresAt   types.String `tfsdk:"expires_at"`   // RFC 3339 time vStack removes the snapshot at, or empty.
+	Used        types.Int64  `tfsdk:"used"`         // Space used by the snapshot, in bytes.
+}
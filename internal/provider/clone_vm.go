@@ -0,0 +1,126 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// CloneVM clones a VM from the source of the plan instead of creating it from an OS profile.
+// The settings and the guest customization of the clone are taken from the "vms-create" parameters
+// built for the plan; settings unknown in the plan are inherited from the source VM.
+//
+// Parameters:
+// - ctx: The context of the operation.
+// - plan: The Terraform plan with a non-nil Source.
+// - params: The parameters the VM would be created with from scratch.
+//
+// Returns:
+// - The result of the "vms-clone" request, describing the clone like a created VM.
+// - An error if the source snapshot ID is invalid or the API request fails.
+func (r *VstackVMResource) CloneVM(
+	ctx context.Context,
+	plan models.VMResourceModel,
+	params vstack_api.VmCreateParams,
+) (vstack_api.VmCreateResult, error) {
+	// 1. Resolve the source VM, or the VM and dataset of the source snapshot
+	cloneParams := vstack_api.VmsCloneParams{
+		SourceID:     plan.Source.VmID.ValueInt64(),
+		Linked:       plan.Source.Linked.ValueBool(),
+		Name:         params.Name,
+		Description:  params.Description,
+		CPUs:         params.CPUs,
+		RAM:          params.RAM,
+		BootMedia:    params.BootMedia,
+		VcpuClass:    params.VcpuClass,
+		VdcID:        params.VdcID,
+		PoolSelector: params.PoolSelector,
+		Guest:        params.Guest,
+	}
+	if !plan.Source.SnapshotID.IsNull() {
+		vmID, dataset, name, err := helper.ParseSnapshotImportID(plan.Source.SnapshotID.ValueString())
+		if err != nil {
+			return vstack_api.VmCreateResult{}, fmt.Errorf("CloneVM: %w", err)
+		}
+		cloneParams.SourceID = vmID
+		cloneParams.SourceDataset = dataset
+		cloneParams.SourceSnapshot = name
+	}
+
+	// 2. Only override the CPU priority of the source if it is configured
+	if !plan.CPUPriority.IsNull() && !plan.CPUPriority.IsUnknown() {
+		cloneParams.CpuPriority = plan.CPUPriority.ValueInt64()
+	}
+
+	// 3. Clone the VM
+	result, err := r.Client.VmsClone(ctx, cloneParams)
+	if err != nil {
+		return result, fmt.Errorf("CloneVM: %w", err)
+	}
+
+	tflog.SubsystemInfo(ctx, logSubsystemVM, "Cloned VM", map[string]any{
+		"vm_id":           result.Data.ID,
+		"source_id":       cloneParams.SourceID,
+		"source_snapshot": cloneParams.SourceSnapshot,
+		"linked":          cloneParams.Linked,
+	})
+
+	return result, nil
+}
+
+// ReconcileClonedDisks adds, resizes and removes the disks of a new clone to match the disks of the plan.
+// The clone is not started yet, so the disks are changed while it is stopped.
+func (r *VstackVMResource) ReconcileClonedDisks(ctx context.Context, plan *models.VMResourceModel, vmID int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// 1. Retrieve the disks the clone got from its source
+	apiResponse, err := r.Client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
+	if err != nil {
+		diags.AddError("Error on vstack_api.VmGet func", err.Error())
+		return diags
+	}
+	cloned, err := helper.MapRespToState(apiResponse, *plan)
+	if err != nil {
+		diags.AddError("Error mapping cloned VM to state", err.Error())
+		return diags
+	}
+
+	// The clone is not started yet, so disks are removed without stopping it.
+	cloned.OperStatus = types.Int64Value(helper.Status.Offline)
+
+	// 2. Settings not configured for a disk of the source are inherited from it
+	clonedDisksBySlot := make(map[int64]models.DiskModel)
+	for _, disk := range cloned.Disks {
+		clonedDisksBySlot[disk.Slot.ValueInt64()] = disk
+	}
+	for i, disk := range plan.Disks {
+		clonedDisk, exists := clonedDisksBySlot[disk.Slot.ValueInt64()]
+		if !exists {
+			continue
+		}
+		if disk.IopsLimit.IsUnknown() {
+			plan.Disks[i].IopsLimit = clonedDisk.IopsLimit
+		}
+		if disk.MbpsLimit.IsUnknown() {
+			plan.Disks[i].MbpsLimit = clonedDisk.MbpsLimit
+		}
+		if disk.Label.IsUnknown() {
+			plan.Disks[i].Label = clonedDisk.Label
+		}
+		if disk.SectorSize.IsUnknown() {
+			plan.Disks[i].SectorSize = clonedDisk.SectorSize
+		}
+	}
+
+	// 3. Synchronize them with the plan
+	diags.Append(r.UpdateDisks(ctx, plan, &cloned)...)
+	return diags
+}
@@ -8,17 +8,13 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
-	"net/http"
-	"terraform-provider-vstack/internal/helper"
 	"terraform-provider-vstack/internal/models"
 	"terraform-provider-vstack/internal/vstack_api"
 )
 
 // vStackVMProfileDataSource implements a data source for retrieving OS types and their profiles.
 type VstackVMProfileDataSource struct {
-	Client     *http.Client
-	BaseURL    string
-	AuthCookie string
+	Client *vstack_api.Client
 }
 
 // Ensure VstackVMProfileDataSource satisfies the Terraform interfaces.
@@ -53,8 +49,6 @@ func (d *VstackVMProfileDataSource) Configure(ctx context.Context, req datasourc
 	}
 
 	d.Client = providerData.client
-	d.BaseURL = providerData.Host
-	d.AuthCookie = providerData.authCookie
 }
 
 // Schema defines the structure of the data source.
@@ -99,20 +93,17 @@ func (d *VstackVMProfileDataSource) Schema(ctx context.Context, req datasource.S
 
 // Read sends a request to the "vm-profiles" method and converts the response into Terraform structures.
 func (d *VstackVMProfileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	// 1. Prepare the request payload.
-	requestPayload := helper.BuildJSONRPCRequest("vm-profiles", nil)
-
-	// 2. Send the request.
-	apiResponse, err := vstack_api.VmProfiles(requestPayload, d.AuthCookie, d.BaseURL, d.Client)
+	// 1. Send the request.
+	apiResponse, err := d.Client.VmProfiles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Error retrieving VM profiles", err.Error())
 		return
 	}
 
-	// 3. Create the top-level model to store all OS types.
+	// 2. Create the top-level model to store all OS types.
 	var result models.VMProfileDataModel
 
-	// 4. Convert the API response to Go structures.
+	// 3. Convert the API response to Go structures.
 	for _, osType := range apiResponse.Data {
 		// Collect the list of profiles.
 		var profiles []models.ProfileModel
@@ -133,7 +124,7 @@ func (d *VstackVMProfileDataSource) Read(ctx context.Context, req datasource.Rea
 		})
 	}
 
-	// 5. Save the result in the Terraform state.
+	// 4. Save the result in the Terraform state.
 	diags := resp.State.Set(ctx, &result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -0,0 +1,174 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// VstackNetworkDataSource implements a data source resolving a network by its ID, name or VDC.
+type VstackNetworkDataSource struct {
+	Client *vstack_api.Client
+}
+
+// Ensure VstackNetworkDataSource satisfies the Terraform interfaces.
+var (
+	_ datasource.DataSource              = &VstackNetworkDataSource{}
+	_ datasource.DataSourceWithConfigure = &VstackNetworkDataSource{}
+)
+
+// NewVstackNetworkDataSource initializes the data source.
+func NewVstackNetworkDataSource() datasource.DataSource {
+	return &VstackNetworkDataSource{}
+}
+
+// Metadata sets the name of the data source.
+func (d *VstackNetworkDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network"
+}
+
+// Configure sets up the data source with provider-specific settings.
+func (d *VstackNetworkDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*VStackProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data Type",
+			fmt.Sprintf("Expected *VStackProvider, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.Client = providerData.client
+}
+
+// Schema defines the structure of the data source.
+func (d *VstackNetworkDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a vStack network by its ID, name or VDC. Exactly one network must match the given arguments.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Description: "Unique identifier of the network.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the network.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"vdc_id": schema.Int64Attribute{
+				Description: "Virtual Data Center ID owning the network.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "Description of the network.",
+				Computed:    true,
+			},
+			"type": schema.StringAttribute{
+				Description: "Network type: vlan or vxlan.",
+				Computed:    true,
+			},
+			"tag": schema.Int64Attribute{
+				Description: "VLAN ID or VXLAN network identifier (VNI).",
+				Computed:    true,
+			},
+			"cidr": schema.StringAttribute{
+				Description: "Subnet of the network in CIDR notation.",
+				Computed:    true,
+			},
+			"gateway": schema.StringAttribute{
+				Description: "Default gateway address of the network. Empty if the network has no gateway.",
+				Computed:    true,
+			},
+			"dhcp": schema.SingleNestedAttribute{
+				Description: "DHCP settings of the network. Null if DHCP is disabled.",
+				Computed:    true,
+				Attributes: map[string]schema.Attribute{
+					"enabled": schema.BoolAttribute{
+						Description: "Whether DHCP is enabled.",
+						Computed:    true,
+					},
+					"range_start": schema.StringAttribute{
+						Description: "First address leased by DHCP.",
+						Computed:    true,
+					},
+					"range_end": schema.StringAttribute{
+						Description: "Last address leased by DHCP.",
+						Computed:    true,
+					},
+					"dns_servers": schema.ListAttribute{
+						Description: "DNS servers announced by DHCP.",
+						ElementType: types.StringType,
+						Computed:    true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read resolves the network matching the configured arguments and stores its attributes in the state.
+func (d *VstackNetworkDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemNetwork)
+
+	var config models.NetworkDataSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 1. Build the filter from the configured arguments.
+	filter := helper.NetworkFilter{
+		ID:    config.ID.ValueInt64(),
+		Name:  config.Name.ValueString(),
+		VdcID: config.VdcID.ValueInt64(),
+	}
+	if filter == (helper.NetworkFilter{}) {
+		resp.Diagnostics.AddError("Missing network filter", "At least one of id, name or vdc_id must be set.")
+		return
+	}
+
+	// 2. Find the only matching network.
+	network, err := helper.FindNetwork(ctx, d.Client, filter)
+	if err != nil {
+		resp.Diagnostics.AddError("Error looking up network", err.Error())
+		return
+	}
+
+	// 3. Save the network in the Terraform state.
+	state := models.NetworkDataSourceModel{
+		ID:          types.Int64Value(network.ID),
+		Name:        types.StringValue(network.Name),
+		Description: types.StringValue(network.Description),
+		VdcID:       types.Int64Value(network.VdcID),
+		Type:        types.StringValue(network.Type),
+		Tag:         types.Int64Value(network.Tag),
+		CIDR:        types.StringValue(network.CIDR),
+		Gateway:     types.StringValue(network.Gateway),
+		DHCP:        helper.MapNetworkDHCP(network.DHCP, nil),
+	}
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.SubsystemDebug(ctx, logSubsystemNetwork, "Resolved network", map[string]any{"network_id": network.ID, "filter": filter.String()})
+}
@@ -47,6 +47,11 @@ data "vstack_vm_get" "test" {
 	id = vstack_vm.test.id
     depends_on      = [vstack_vm.test]
 }
+
+data "vstack_vm_get" "by_name" {
+  name   = vstack_vm.test.name
+  vdc_id = vstack_vm.test.vdc_id
+}
 `
 	// Combine the provider configuration template with the data source configuration template.
 	dataSourceConfig := providerConfigTemplate + dataSourceConfigTemplate
@@ -98,6 +103,10 @@ data "vstack_vm_get" "test" {
 					resource.TestCheckResourceAttrSet("data.vstack_vm_get.test", "disks.0.label"),
 					resource.TestCheckResourceAttrSet("data.vstack_vm_get.test", "disks.0.sector_size.logical"),
 					resource.TestCheckResourceAttrSet("data.vstack_vm_get.test", "disks.0.sector_size.physical"),
+
+					// Ensure that the lookup by name finds the same VM.
+					resource.TestCheckResourceAttrPair("data.vstack_vm_get.by_name", "id", "vstack_vm.test", "id"),
+					resource.TestCheckResourceAttrPair("data.vstack_vm_get.by_name", "cpus", "data.vstack_vm_get.test", "cpus"),
 				),
 			},
 		},
@@ -0,0 +1,152 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// VstackVMSnapshotsDataSource implements a data source listing the snapshots of a VM.
+type VstackVMSnapshotsDataSource struct {
+	Client *vstack_api.Client
+}
+
+// Ensure VstackVMSnapshotsDataSource satisfies the Terraform interfaces.
+var (
+	_ datasource.DataSource              = &VstackVMSnapshotsDataSource{}
+	_ datasource.DataSourceWithConfigure = &VstackVMSnapshotsDataSource{}
+)
+
+// NewVstackVMSnapshotsDataSource initializes the data source.
+func NewVstackVMSnapshotsDataSource() datasource.DataSource {
+	return &VstackVMSnapshotsDataSource{}
+}
+
+// Metadata sets the name of the data source.
+func (d *VstackVMSnapshotsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_snapshots"
+}
+
+// Configure sets up the data source with provider-specific settings.
+func (d *VstackVMSnapshotsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*VStackProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data Type",
+			fmt.Sprintf("Expected *VStackProvider, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.Client = providerData.client
+}
+
+// Schema defines the structure of the data source.
+func (d *VstackVMSnapshotsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the snapshots of a VM, including those not managed by Terraform. Expired snapshots are not listed.",
+		Attributes: map[string]schema.Attribute{
+			"vm_id": schema.Int64Attribute{
+				Description: "ID of the VM.",
+				Required:    true,
+			},
+			"root_dataset": schema.StringAttribute{
+				Description: "Only list the snapshots of this dataset of the VM.",
+				Optional:    true,
+			},
+			"snapshots": schema.ListNestedAttribute{
+				Description: "Snapshots of the VM from the oldest to the newest.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "Identifier of the snapshot: <vm_id>/<root_dataset>@<name>. Use it to import the snapshot.",
+							Computed:    true,
+						},
+						"root_dataset": schema.StringAttribute{
+							Description: "Snapshotted dataset of the VM.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "Name of the snapshot.",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "Description of the snapshot.",
+							Computed:    true,
+						},
+						"created_at": schema.StringAttribute{
+							Description: "Time the snapshot was taken at, in RFC 3339 format.",
+							Computed:    true,
+						},
+						"expires_at": schema.StringAttribute{
+							Description: "Time vStack removes the snapshot at, in RFC 3339 format. Empty if the snapshot has no retention.",
+							Computed:    true,
+						},
+						"used": schema.Int64Attribute{
+							Description: "Space used by the snapshot, in bytes.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read lists the snapshots of the VM and stores them in the state.
+func (d *VstackVMSnapshotsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state models.VMSnapshotsDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 1. List the snapshots
+	vmID := state.VmID.ValueInt64()
+	listResp, err := d.Client.VmSnapshotsList(ctx, vstack_api.VmSnapshotsListParams{ID: vmID, Dataset: state.RootDataset.ValueString()})
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing VM snapshots", err.Error())
+		return
+	}
+	snapshots := listResp.Data
+	helper.SortVMSnapshots(snapshots)
+
+	// 2. Map the snapshots to the state
+	state.Snapshots = make([]models.VMSnapshotListItemModel, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		state.Snapshots = append(state.Snapshots, models.VMSnapshotListItemModel{
+			ID:          types.StringValue(helper.SnapshotID(snapshot.VmID, snapshot.Dataset, snapshot.Name)),
+			RootDataset: types.StringValue(snapshot.Dataset),
+			Name:        types.StringValue(snapshot.Name),
+			Description: types.StringValue(snapshot.Description),
+			CreatedAt:   types.StringValue(helper.FormatSnapshotTime(snapshot.Created)),
+			ExpiresAt:   types.StringValue(helper.FormatSnapshotTime(snapshot.Expires)),
+			Used:        types.Int64Value(snapshot.Used),
+		})
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Listed VM snapshots", map[string]any{"vm_id": vmID, "count": len(snapshots)})
+}
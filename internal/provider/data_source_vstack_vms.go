@@ -0,0 +1,161 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// VstackVMsDataSource implements a data source listing the VMs matching a set of filters.
+type VstackVMsDataSource struct {
+	Client *vstack_api.Client
+}
+
+// Ensure VstackVMsDataSource satisfies the Terraform interfaces.
+var (
+	_ datasource.DataSource              = &VstackVMsDataSource{}
+	_ datasource.DataSourceWithConfigure = &VstackVMsDataSource{}
+)
+
+// NewVstackVMsDataSource initializes the data source.
+func NewVstackVMsDataSource() datasource.DataSource {
+	return &VstackVMsDataSource{}
+}
+
+// Metadata sets the name of the data source.
+func (d *VstackVMsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vms"
+}
+
+// Configure sets up the data source with provider-specific settings.
+func (d *VstackVMsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*VStackProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data Type",
+			fmt.Sprintf("Expected *VStackProvider, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.Client = providerData.client
+}
+
+// Schema defines the structure of the data source. Every VM in vms has the attributes of vstack_vm_get.
+func (d *VstackVMsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the VMs matching all the given filters. Without filters, all VMs visible to the user are listed.",
+		Attributes: map[string]schema.Attribute{
+			"vdc_id": schema.Int64Attribute{
+				Description: "Only list VMs of this Virtual Data Center.",
+				Optional:    true,
+			},
+			"node": schema.Int64Attribute{
+				Description: "Only list VMs on this node.",
+				Optional:    true,
+			},
+			"pool": schema.StringAttribute{
+				Description: "Only list VMs in this pool (the pool_selector of the VM).",
+				Optional:    true,
+			},
+			"name_regex": schema.StringAttribute{
+				Description: "Only list VMs whose name matches this regular expression (RE2 syntax, unanchored).",
+				Optional:    true,
+			},
+			"oper_status": schema.Int64Attribute{
+				Description: "Only list VMs with this operational status, e.g. 3 for started VMs.",
+				Optional:    true,
+			},
+			"tag": schema.StringAttribute{
+				Description: "Only list VMs carrying this tag.",
+				Optional:    true,
+			},
+			"ids": schema.ListAttribute{
+				Description: "IDs of the matching VMs in ascending order.",
+				ElementType: types.Int64Type,
+				Computed:    true,
+			},
+			"vms": schema.ListNestedAttribute{
+				Description: "Matching VMs in ascending order of their IDs, with the attributes of vstack_vm_get.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: vmDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// Read lists the matching VMs and stores their details in the state.
+func (d *VstackVMsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state models.VMsDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 1. Build the filter from the configuration.
+	filter := helper.VMFilter{
+		VdcID: state.VdcID.ValueInt64(),
+		Node:  state.Node.ValueInt64(),
+		Pool:  state.Pool.ValueString(),
+		Tag:   state.Tag.ValueString(),
+	}
+	if !state.NameRegex.IsNull() {
+		nameRegex, err := regexp.Compile(state.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid name_regex", err.Error())
+			return
+		}
+		filter.NameRegex = nameRegex
+	}
+	if !state.OperStatus.IsNull() {
+		filter.OperStatus = state.OperStatus.ValueInt64Pointer()
+	}
+
+	// 2. List the matching VMs with their details.
+	vms, err := helper.ListVMs(ctx, d.Client, filter)
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing VMs", err.Error())
+		return
+	}
+
+	// 3. Map the VMs to the state.
+	state.IDs = make([]types.Int64, 0, len(vms))
+	state.VMs = make([]models.VMDataSourceModel, 0, len(vms))
+	for _, vm := range vms {
+		model, err := helper.MapRespToDataSourceModel(vm)
+		if err != nil {
+			resp.Diagnostics.AddError("Error mapping VM to state", fmt.Sprintf("VM %d: %s", vm.Data.ID, err))
+			return
+		}
+		state.IDs = append(state.IDs, model.ID)
+		state.VMs = append(state.VMs, model)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Listed VMs", map[string]any{"count": len(vms)})
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccVStackVMsDataSource(t *testing.T) {
+	// Two VMs are created and listed by a name regex matching only them.
+	dataSourceConfigTemplate := `
+resource "vstack_vm" "test" {
+  count      = 2
+  name       = "tf-acc-vms-${count.index}"
+  cpus       = 1
+  ram        = 2048
+  os_profile = var.os_profile
+  vdc_id     = var.vdc_id
+
+  disks = [
+    {
+      size = 20
+      slot = 1
+    }
+  ]
+
+  guest = {
+    hostname = "tf-acc-vms-${count.index}"
+    users = {
+      root = {
+        ssh_authorized_keys = []
+        password            = "rootpassword"
+      }
+    }
+  }
+}
+
+data "vstack_vms" "test" {
+  vdc_id     = var.vdc_id
+  name_regex = "^tf-acc-vms-[0-9]+$"
+
+  depends_on = [vstack_vm.test]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfigTemplate + dataSourceConfigTemplate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vstack_vms.test", "ids.#", "2"),
+					resource.TestCheckResourceAttr("data.vstack_vms.test", "vms.#", "2"),
+					resource.TestCheckTypeSetElemAttrPair("data.vstack_vms.test", "ids.*", "vstack_vm.test.0", "id"),
+					resource.TestCheckTypeSetElemAttrPair("data.vstack_vms.test", "ids.*", "vstack_vm.test.1", "id"),
+					resource.TestCheckResourceAttrSet("data.vstack_vms.test", "vms.0.name"),
+					resource.TestCheckResourceAttrSet("data.vstack_vms.test", "vms.0.oper_status"),
+					resource.TestCheckResourceAttr("data.vstack_vms.test", "vms.1.disks.0.size", "20"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,25 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// Names of the tflog subsystems used by the resources.
+const (
+	logSubsystemVM       = "vm"
+	logSubsystemNIC      = "nic"
+	logSubsystemNetwork  = "network"
+	logSubsystemSnapshot = "snapshot"
+)
+
+// withLogSubsystem returns ctx with the given tflog subsystem, masking the same sensitive fields as the API client.
+func withLogSubsystem(ctx context.Context, subsystem string) context.Context {
+	ctx = tflog.NewSubsystem(ctx, subsystem)
+	return tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, subsystem, vstack_api.SensitiveLogKeys...)
+}
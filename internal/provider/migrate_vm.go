@@ -0,0 +1,72 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/models"
+)
+
+// MigrateVM moves the VM to another node. A running VM is migrated live if migrate_on_update is set;
+// otherwise it is only stopped for an offline migration and started again if allow_reboot_for_update is set.
+// A VM that is not running is migrated offline.
+//
+// Parameters:
+// - ctx: The context of the operation.
+// - plan: The Terraform plan of the VM.
+// - vmID: The ID of the VM.
+// - node: The ID of the node to move the VM to.
+//
+// Returns:
+// - Diagnostics with an error if the VM could not be migrated.
+func (r *VstackVMResource) MigrateVM(ctx context.Context, plan models.VMResourceModel, vmID, node int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// 1. Migrate a running VM live
+	running, err := helper.CheckIfVMIsRunning(ctx, r.Client, vmID)
+	if err != nil {
+		diags.AddError("Error checking VM status", err.Error())
+		return diags
+	}
+	if running && plan.MigrateOnUpdate.ValueBool() {
+		if err := helper.MigrateVM(ctx, r.Client, vmID, node, true, r.StatusTimeout); err != nil {
+			diags.AddError("Error migrating VM", vmFailureDetail(err))
+			return diags
+		}
+		tflog.SubsystemInfo(ctx, logSubsystemVM, "Migrated VM live", map[string]any{"vm_id": vmID, "node": node})
+		return diags
+	}
+
+	// 2. Otherwise migrate it offline, stopping and starting a running VM only if it is allowed
+	opts := helper.HotplugOptions{
+		RestartPolicy: helper.RestartPolicyNever,
+		StatusTimeout: r.StatusTimeout,
+	}
+	if plan.AllowRebootForUpdate.ValueBool() {
+		opts.RestartPolicy = helper.RestartPolicyIfNeeded
+	}
+	restarted, err := helper.ApplyHardwareChange(ctx, r.Client, vmID, opts, func(ctx context.Context) error {
+		return helper.MigrateVM(ctx, r.Client, vmID, node, false, r.StatusTimeout)
+	})
+
+	var forbiddenErr *helper.RestartForbiddenError
+	switch {
+	case errors.As(err, &forbiddenErr):
+		diags.AddError("VM must be restarted to migrate it",
+			fmt.Sprintf("VM %d is running and migrate_on_update is disabled. Set migrate_on_update = true to migrate it live, "+
+				"or allow_reboot_for_update = true to let the provider stop it for the migration and start it again.", vmID))
+		return diags
+	case err != nil:
+		diags.AddError("Error migrating VM", vmFailureDetail(err))
+		return diags
+	}
+
+	tflog.SubsystemInfo(ctx, logSubsystemVM, "Migrated VM offline", map[string]any{"vm_id": vmID, "node": node, "restarted": restarted})
+	return diags
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+
+	"terraform-provider-vstack/internal/models"
+)
+
+// nicReattachNeeded reports whether the configuration changes a NIC setting that is applied by detaching
+// the port and attaching it again: network_id, address or ip_guard. Unset optional settings keep their value.
+func nicReattachNeeded(config, state models.NetworkPortModel) bool {
+	if config.NetworkID.IsUnknown() || !config.NetworkID.Equal(state.NetworkID) {
+		return true
+	}
+	if !config.Address.IsNull() && !config.Address.Equal(state.Address) {
+		return true
+	}
+	if !config.IpGuard.IsNull() && !config.IpGuard.Equal(state.IpGuard) {
+		return true
+	}
+	return false
+}
+
+// nicReattachPlanned reads the configuration and the prior state of a NIC and reports whether it is re-attached.
+func nicReattachPlanned(ctx context.Context, config tfsdk.Config, state tfsdk.State) (bool, diag.Diagnostics) {
+	var configModel, stateModel models.NetworkPortModel
+	diags := config.Get(ctx, &configModel)
+	diags.Append(state.Get(ctx, &stateModel)...)
+	if diags.HasError() {
+		return false, diags
+	}
+	return nicReattachNeeded(configModel, stateModel), diags
+}
+
+// useStateUnlessNicReattached copies the prior state value into the plan of a computed NIC attribute,
+// unless the NIC is re-attached, which assigns it a new value (e.g. a new port ID).
+type useStateUnlessNicReattached struct{}
+
+var (
+	_ planmodifier.Int64  = useStateUnlessNicReattached{}
+	_ planmodifier.String = useStateUnlessNicReattached{}
+)
+
+// Description returns a plain text description of the modifier's behavior.
+func (m useStateUnlessNicReattached) Description(_ context.Context) string {
+	return "Keeps the prior state value unless network_id, address or ip_guard change."
+}
+
+// MarkdownDescription returns a markdown description of the modifier's behavior.
+func (m useStateUnlessNicReattached) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+// PlanModifyInt64 implements planmodifier.Int64.
+func (m useStateUnlessNicReattached) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if req.StateValue.IsNull() || !req.PlanValue.IsUnknown() || req.Plan.Raw.IsNull() {
+		return
+	}
+	reattach, diags := nicReattachPlanned(ctx, req.Config, req.State)
+	resp.Diagnostics.Append(diags...)
+	if !reattach && !diags.HasError() {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// PlanModifyString implements planmodifier.String.
+func (m useStateUnlessNicReattached) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.StateValue.IsNull() || !req.PlanValue.IsUnknown() || req.Plan.Raw.IsNull() {
+		return
+	}
+	reattach, diags := nicReattachPlanned(ctx, req.Config, req.State)
+	resp.Diagnostics.Append(diags...)
+	if !reattach && !diags.HasError() {
+		resp.PlanValue = req.StateValue
+	}
+}
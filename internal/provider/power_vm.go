@@ -0,0 +1,90 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"terraform-provider-vstack/internal/helper"
+	"time"
+)
+
+// shutdownGracePeriod returns the configured shutdown_timeout, or zero to use the default grace period.
+// The value is validated as a positive duration by the schema.
+func shutdownGracePeriod(shutdownTimeout types.String) time.Duration {
+	if shutdownTimeout.IsNull() || shutdownTimeout.IsUnknown() {
+		return 0
+	}
+	gracePeriod, err := time.ParseDuration(shutdownTimeout.ValueString())
+	if err != nil {
+		return 0
+	}
+	return gracePeriod
+}
+
+// StopVM stops a running VM with the given action.
+//
+// Parameters:
+// - ctx: The context of the operation.
+// - vmID: The ID of the VM.
+// - action: "stop" or "poweroff" to power the VM off, or "shutdown" to shut it down gracefully first.
+// - shutdownTimeout: The configured grace period of a graceful shutdown.
+//
+// Returns:
+// - An error if the VM could not be stopped.
+func (r *VstackVMResource) StopVM(ctx context.Context, vmID int64, action string, shutdownTimeout types.String) error {
+	if action != "shutdown" {
+		if err := helper.PerformAction(ctx, r.Client, vmID, action, r.StatusTimeout); err != nil {
+			return fmt.Errorf("StopVM: %w", err)
+		}
+		return nil
+	}
+
+	forced, err := helper.ShutdownVM(ctx, r.Client, vmID, shutdownGracePeriod(shutdownTimeout), r.StatusTimeout)
+	if err != nil {
+		return fmt.Errorf("StopVM: %w", err)
+	}
+	if forced {
+		tflog.SubsystemWarn(ctx, logSubsystemVM, "Guest did not shut down in time, VM was powered off", map[string]any{
+			"vm_id": vmID,
+		})
+	}
+	return nil
+}
+
+// RebootVM shuts a running VM down gracefully and starts it again.
+// A VM that is not running is left as it is.
+func (r *VstackVMResource) RebootVM(ctx context.Context, vmID int64, shutdownTimeout types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// 1. Only a running VM is rebooted
+	operStatus, err := helper.GetVMOperStatus(ctx, r.Client, vmID)
+	if err != nil {
+		diags.AddError("Error checking VM status", err.Error())
+		return diags
+	}
+	if operStatus != helper.Status.Started {
+		tflog.SubsystemInfo(ctx, logSubsystemVM, "Reboot trigger changed, but the VM is not running", map[string]any{
+			"vm_id":       vmID,
+			"oper_status": operStatus,
+		})
+		return diags
+	}
+
+	// 2. Shut it down and start it again
+	if err := r.StopVM(ctx, vmID, "shutdown", shutdownTimeout); err != nil {
+		diags.AddError("Error shutting down VM for reboot", vmFailureDetail(err))
+		return diags
+	}
+	if err := helper.PerformAction(ctx, r.Client, vmID, "start", r.StatusTimeout); err != nil {
+		diags.AddError("Error starting VM after reboot", vmFailureDetail(err))
+		return diags
+	}
+
+	tflog.SubsystemInfo(ctx, logSubsystemVM, "Rebooted VM", map[string]any{"vm_id": vmID})
+	return diags
+}
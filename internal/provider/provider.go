@@ -4,19 +4,24 @@
 package provider
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/vstack_api"
 )
 
 // Ensure VStackProvider satisfies various provider interfaces.
@@ -27,17 +32,36 @@ type VStackProvider struct {
 	// version is set to the provider version on release, "dev" when the
 	// provider is built and run locally, and "test" when running acceptance
 	// testing.
-	version    string
-	authCookie string
-	client     *http.Client
-	Host       string
+	version       string
+	client        *vstack_api.Client
+	statusTimeout time.Duration
+	lockConfig    helper.VMLockConfig
+	nicHotplug    bool
 }
 
 // VStackProviderModel describes the provider data model.
 type VStackProviderModel struct {
-	Host     types.String `tfsdk:"host"`
-	Username types.String `tfsdk:"username"`
-	Password types.String `tfsdk:"password"`
+	Host           types.String `tfsdk:"host"`
+	Username       types.String `tfsdk:"username"`
+	Password       types.String `tfsdk:"password"`
+	ConnectTimeout types.String `tfsdk:"connect_timeout"`
+	ReadTimeout    types.String `tfsdk:"read_timeout"`
+	MaxRetries     types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait   types.String `tfsdk:"retry_max_wait"`
+	StatusTimeout  types.String `tfsdk:"status_timeout"`
+
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	ClientCert         types.String `tfsdk:"client_cert"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+
+	LockBackend types.String `tfsdk:"lock_backend"`
+	LockDir     types.String `tfsdk:"lock_dir"`
+	LockTimeout types.String `tfsdk:"lock_timeout"`
+
+	NicHotplug types.Bool `tfsdk:"nic_hotplug"`
 }
 
 // Metadata sets the type name and version for the provider.
@@ -51,121 +75,423 @@ func (p *VStackProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				MarkdownDescription: "API host for the vStack provider.",
-				Required:            true,
+				MarkdownDescription: "API host for the vStack provider. Can also be set with the `VSTACK_HOST` environment variable.",
+				Optional:            true,
 			},
 			"username": schema.StringAttribute{
-				MarkdownDescription: "Username for vStack API.",
-				Required:            true,
+				MarkdownDescription: "Username for vStack API. Can also be set with the `VSTACK_USERNAME` environment variable.",
+				Optional:            true,
 			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "Password for vStack API.",
-				Required:            true,
+				MarkdownDescription: "Password for vStack API. Can also be set with the `VSTACK_PASSWORD` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"connect_timeout": schema.StringAttribute{
+				MarkdownDescription: "Timeout for establishing a connection to the vStack API, as a Go duration string (e.g. `30s`). Defaults to `30s`. Can also be set with the `VSTACK_CONNECT_TIMEOUT` environment variable.",
+				Optional:            true,
+			},
+			"read_timeout": schema.StringAttribute{
+				MarkdownDescription: "Timeout for a single vStack API request, including reading the response, as a Go duration string (e.g. `5m`). Defaults to `5m`. Can also be set with the `VSTACK_READ_TIMEOUT` environment variable.",
+				Optional:            true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of retries of a vStack API request that failed with a transient error. Set to `0` to disable retries. Defaults to `3`. Can also be set with the `VSTACK_MAX_RETRIES` environment variable.",
+				Optional:            true,
+			},
+			"retry_max_wait": schema.StringAttribute{
+				MarkdownDescription: "Maximum backoff between two attempts of a vStack API request, as a Go duration string (e.g. `30s`). Defaults to `30s`. Can also be set with the `VSTACK_RETRY_MAX_WAIT` environment variable.",
+				Optional:            true,
+			},
+			"status_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time to wait for a VM to reach the requested operational status after it is started or stopped, as a Go duration string (e.g. `10m`). Defaults to `10m`. Can also be set with the `VSTACK_STATUS_TIMEOUT` environment variable.",
+				Optional:            true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM file with CA certificates used to verify the vStack API certificate, in addition to the system trust store. Can also be set with the `VSTACK_CA_CERT_FILE` environment variable.",
+				Optional:            true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded CA certificates used to verify the vStack API certificate, in addition to the system trust store. Can also be set with the `VSTACK_CA_CERT_PEM` environment variable.",
+				Optional:            true,
+			},
+			"client_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client certificate for mutual TLS, or a path to a file containing it. Requires `client_key`. Can also be set with the `VSTACK_CLIENT_CERT` environment variable.",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded private key of `client_cert`, or a path to a file containing it. Can also be set with the `VSTACK_CLIENT_KEY` environment variable.",
+				Optional:            true,
 				Sensitive:           true,
 			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Disables verification of the vStack API certificate. Use only for lab clusters. Defaults to `false`. Can also be set with the `VSTACK_INSECURE_SKIP_VERIFY` environment variable.",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the HTTP proxy used to reach the vStack API, e.g. `http://proxy.example.com:3128`. If not set, the `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables are used. Can also be set with the `VSTACK_PROXY_URL` environment variable.",
+				Optional:            true,
+			},
+			"lock_backend": schema.StringAttribute{
+				MarkdownDescription: "How operations that stop and start a VM (e.g. adding a NIC) are serialised: `memory` locks VMs inside the provider process only, `file` also locks them for other Terraform runs on the same machine using lock files in `lock_dir`, `lease` also locks them for Terraform runs on other machines using a lease stored in the VM description. Defaults to `memory`. Can also be set with the `VSTACK_LOCK_BACKEND` environment variable.",
+				Optional:            true,
+			},
+			"lock_dir": schema.StringAttribute{
+				MarkdownDescription: "Directory of the lock files of the `file` lock backend. Defaults to `terraform-provider-vstack-locks` in the temporary directory of the OS. Can also be set with the `VSTACK_LOCK_DIR` environment variable.",
+				Optional:            true,
+			},
+			"lock_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time to wait for a VM locked by another operation, as a Go duration string (e.g. `15m`). Defaults to `15m`. Can also be set with the `VSTACK_LOCK_TIMEOUT` environment variable.",
+				Optional:            true,
+			},
+			"nic_hotplug": schema.BoolAttribute{
+				MarkdownDescription: "Default of the `hotplug` argument of `vstack_nic`: whether NICs are attached to and detached from running VMs without stopping them first. Defaults to `true`. Can also be set with the `VSTACK_NIC_HOTPLUG` environment variable.",
+				Optional:            true,
+			},
 		},
 	}
 }
 
+// Environment variables used when the corresponding provider argument is not set in the configuration.
+const (
+	envHost           = "VSTACK_HOST"
+	envUsername       = "VSTACK_USERNAME"
+	envPassword       = "VSTACK_PASSWORD"
+	envConnectTimeout = "VSTACK_CONNECT_TIMEOUT"
+	envReadTimeout    = "VSTACK_READ_TIMEOUT"
+	envMaxRetries     = "VSTACK_MAX_RETRIES"
+	envRetryMaxWait   = "VSTACK_RETRY_MAX_WAIT"
+	envStatusTimeout  = "VSTACK_STATUS_TIMEOUT"
+
+	envCACertFile         = "VSTACK_CA_CERT_FILE"
+	envCACertPEM          = "VSTACK_CA_CERT_PEM"
+	envClientCert         = "VSTACK_CLIENT_CERT"
+	envClientKey          = "VSTACK_CLIENT_KEY"
+	envInsecureSkipVerify = "VSTACK_INSECURE_SKIP_VERIFY"
+	envProxyURL           = "VSTACK_PROXY_URL"
+
+	envLockBackend = "VSTACK_LOCK_BACKEND"
+	envLockDir     = "VSTACK_LOCK_DIR"
+	envLockTimeout = "VSTACK_LOCK_TIMEOUT"
+
+	envNicHotplug = "VSTACK_NIC_HOTPLUG"
+)
+
+// Values of the lock_backend provider argument.
+const (
+	lockBackendMemory = "memory"
+	lockBackendFile   = "file"
+	lockBackendLease  = "lease"
+)
+
+// providerSetting is a provider argument resolved from the configuration or from its environment variable.
+type providerSetting struct {
+	name   string // Name of the provider argument, e.g. "host".
+	envVar string // Environment variable used as a fallback, e.g. "VSTACK_HOST".
+	value  string // Resolved value; empty if the argument is not set.
+	source string // Human-readable origin of the value, used in diagnostics.
+}
+
+// isSet reports whether a value was found in the configuration or in the environment.
+func (s providerSetting) isSet() bool {
+	return s.value != ""
+}
+
+// describe returns the origin of the value for diagnostics, e.g. `the "host" argument`.
+func (s providerSetting) describe() string {
+	if s.source == "" {
+		return fmt.Sprintf("the %q argument", s.name)
+	}
+	return s.source
+}
+
+// resolveSetting returns the configured value of a string argument, falling back to envVar
+// when the argument is null.
+func resolveSetting(name string, value types.String, envVar string) providerSetting {
+	setting := providerSetting{name: name, envVar: envVar}
+	if !value.IsNull() && !value.IsUnknown() {
+		setting.value = value.ValueString()
+		setting.source = fmt.Sprintf("the %q argument", name)
+		return setting
+	}
+	if env, ok := os.LookupEnv(envVar); ok && env != "" {
+		setting.value = env
+		setting.source = fmt.Sprintf("the %s environment variable", envVar)
+	}
+	return setting
+}
+
 // Configure initializes the provider with the provided configuration.
-// It authenticates with the vStack API and stores the authentication cookie.
+// Arguments that are not set in the configuration are read from the VSTACK_* environment variables.
+// It authenticates with the vStack API and shares the authenticated API client.
 func (p *VStackProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, vstack_api.SensitiveLogKeys...)
 	tflog.Info(ctx, "Starting provider configuration")
-	fmt.Println("Starting provider configuration")
 
 	var data VStackProviderModel
 
 	// Retrieve configuration values from req.Config
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
-		fmt.Println("Error retrieving provider configuration")
 		return
 	}
 
-	// Initialize HTTP client
-	p.client = &http.Client{}
-	p.authCookie = ""
-	p.Host = data.Host.ValueString()
+	// Values that are unknown during planning cannot be replaced with the environment
+	for _, attribute := range []struct {
+		name    string
+		unknown bool
+	}{
+		{"host", data.Host.IsUnknown()},
+		{"username", data.Username.IsUnknown()},
+		{"password", data.Password.IsUnknown()},
+		{"connect_timeout", data.ConnectTimeout.IsUnknown()},
+		{"read_timeout", data.ReadTimeout.IsUnknown()},
+		{"max_retries", data.MaxRetries.IsUnknown()},
+		{"retry_max_wait", data.RetryMaxWait.IsUnknown()},
+		{"status_timeout", data.StatusTimeout.IsUnknown()},
+		{"ca_cert_file", data.CACertFile.IsUnknown()},
+		{"ca_cert_pem", data.CACertPEM.IsUnknown()},
+		{"client_cert", data.ClientCert.IsUnknown()},
+		{"client_key", data.ClientKey.IsUnknown()},
+		{"insecure_skip_verify", data.InsecureSkipVerify.IsUnknown()},
+		{"proxy_url", data.ProxyURL.IsUnknown()},
+		{"lock_backend", data.LockBackend.IsUnknown()},
+		{"lock_dir", data.LockDir.IsUnknown()},
+		{"lock_timeout", data.LockTimeout.IsUnknown()},
+		{"nic_hotplug", data.NicHotplug.IsUnknown()},
+	} {
+		if attribute.unknown {
+			resp.Diagnostics.AddAttributeError(path.Root(attribute.name), fmt.Sprintf("Unknown %s", attribute.name),
+				fmt.Sprintf("The provider cannot create the vStack API client as there is an unknown configuration value for %q. "+
+					"Either set the value statically in the configuration or use the corresponding VSTACK_* environment variable.", attribute.name))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Prepare JSON-RPC request for authentication using helper function
-	authReq := helper.BuildJSONRPCRequest("auth", map[string]interface{}{
-		"username": data.Username.ValueString(),
-		"password": data.Password.ValueString(),
-	})
+	// Resolve the connection settings
+	host := resolveSetting("host", data.Host, envHost)
+	username := resolveSetting("username", data.Username, envUsername)
+	password := resolveSetting("password", data.Password, envPassword)
+	for _, setting := range []providerSetting{host, username, password} {
+		if !setting.isSet() {
+			resp.Diagnostics.AddAttributeError(path.Root(setting.name), fmt.Sprintf("Missing %s", setting.name),
+				fmt.Sprintf("The vStack API %s is not set. Set the %q argument in the provider configuration or the %s environment variable.",
+					setting.name, setting.name, setting.envVar))
+		}
+	}
+	if host.isSet() {
+		if err := validateHost(host.value); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root(host.name), "Invalid host",
+				fmt.Sprintf("Invalid value %q from %s: %s", host.value, host.describe(), err))
+		}
+	}
 
-	body, err := json.Marshal(authReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error marshaling authentication request", err.Error())
-		return
+	// Parse the timeouts of the API transport
+	connectTimeout := parseDurationSetting(resolveSetting("connect_timeout", data.ConnectTimeout, envConnectTimeout), &resp.Diagnostics)
+	readTimeout := parseDurationSetting(resolveSetting("read_timeout", data.ReadTimeout, envReadTimeout), &resp.Diagnostics)
+
+	// Parse the retry settings of the API client
+	retryPolicy := vstack_api.DefaultRetryPolicy()
+	maxRetriesValue := types.StringNull()
+	if !data.MaxRetries.IsNull() {
+		maxRetriesValue = types.StringValue(strconv.FormatInt(data.MaxRetries.ValueInt64(), 10))
+	}
+	maxRetries := resolveSetting("max_retries", maxRetriesValue, envMaxRetries)
+	if maxRetries.isSet() {
+		n, err := strconv.Atoi(maxRetries.value)
+		switch {
+		case err != nil:
+			resp.Diagnostics.AddAttributeError(path.Root(maxRetries.name), "Invalid max_retries",
+				fmt.Sprintf("Invalid value %q from %s: must be an integer", maxRetries.value, maxRetries.describe()))
+		case n < 0:
+			resp.Diagnostics.AddAttributeError(path.Root(maxRetries.name), "Invalid max_retries",
+				fmt.Sprintf("Invalid value %q from %s: must not be negative", maxRetries.value, maxRetries.describe()))
+		default:
+			retryPolicy.MaxRetries = n
+		}
+	}
+	retryMaxWait := parseDurationSetting(resolveSetting("retry_max_wait", data.RetryMaxWait, envRetryMaxWait), &resp.Diagnostics)
+	if retryMaxWait > 0 {
+		retryPolicy.MaxWait = retryMaxWait
+		if retryPolicy.MinWait > retryMaxWait {
+			retryPolicy.MinWait = retryMaxWait
+		}
 	}
-	reqBody := bytes.NewBuffer(body)
 
-	// Send authentication request
-	authURL := fmt.Sprintf("%s/.api/V4/.req/", p.Host)
-	authRequest, err := http.NewRequest("POST", authURL, reqBody)
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating authentication request", err.Error())
-		return
+	// Parse the time to wait for VM status changes
+	statusTimeout := parseDurationSetting(resolveSetting("status_timeout", data.StatusTimeout, envStatusTimeout), &resp.Diagnostics)
+	if statusTimeout <= 0 {
+		statusTimeout = helper.DefaultStatusTimeout
 	}
-	authRequest.Header.Set("Content-Type", "application/json")
+	p.statusTimeout = statusTimeout
 
-	// Execute authentication request
-	authResponse, err := p.client.Do(authRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error authenticating with vStack", err.Error())
-		return
+	// Resolve the VM lock settings
+	lockBackend := resolveSetting("lock_backend", data.LockBackend, envLockBackend)
+	lockBackendName := strings.ToLower(lockBackend.value)
+	switch lockBackendName {
+	case "":
+		lockBackendName = lockBackendMemory
+	case lockBackendMemory, lockBackendFile, lockBackendLease:
+	default:
+		resp.Diagnostics.AddAttributeError(path.Root(lockBackend.name), "Invalid lock_backend",
+			fmt.Sprintf("Invalid value %q from %s: must be one of %q, %q or %q",
+				lockBackend.value, lockBackend.describe(), lockBackendMemory, lockBackendFile, lockBackendLease))
+	}
+	lockDir := resolveSetting("lock_dir", data.LockDir, envLockDir)
+	if lockDir.isSet() && lockBackendName != lockBackendFile {
+		resp.Diagnostics.AddAttributeWarning(path.Root(lockDir.name), "Unused lock_dir",
+			fmt.Sprintf("lock_dir is set by %s, but it is only used by the %q lock backend.", lockDir.describe(), lockBackendFile))
+	}
+	p.lockConfig = helper.VMLockConfig{
+		Timeout: parseDurationSetting(resolveSetting("lock_timeout", data.LockTimeout, envLockTimeout), &resp.Diagnostics),
 	}
-	defer func() {
-		if closeErr := authResponse.Body.Close(); closeErr != nil {
-			tflog.Error(ctx, "Error closing authentication response body", map[string]any{
-				"error": closeErr.Error(),
-			})
+	if p.lockConfig.Timeout <= 0 {
+		p.lockConfig.Timeout = helper.DefaultLockTimeout
+	}
+
+	// Resolve the default NIC hot-plug mode
+	p.nicHotplug = parseBoolSetting(resolveBoolSetting("nic_hotplug", data.NicHotplug, envNicHotplug), true, &resp.Diagnostics)
+
+	// Resolve the TLS and proxy settings
+	clientCert := resolveSetting("client_cert", data.ClientCert, envClientCert)
+	clientKey := resolveSetting("client_key", data.ClientKey, envClientKey)
+	if clientCert.isSet() != clientKey.isSet() {
+		missing, present := clientKey, clientCert
+		if !clientCert.isSet() {
+			missing, present = clientCert, clientKey
 		}
-	}()
-
-	// Decode authentication response
-	var authResp struct {
-		ID      string `json:"id"`
-		JsonRPC string `json:"jsonrpc"`
-		Result  struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-			Data    struct {
-				Cookie map[string]string `json:"cookie"`
-			} `json:"data"`
-		} `json:"result"`
-	}
-	if err := json.NewDecoder(authResponse.Body).Decode(&authResp); err != nil {
-		resp.Diagnostics.AddError("Error decoding authentication response", err.Error())
-		return
+		resp.Diagnostics.AddAttributeError(path.Root(missing.name), fmt.Sprintf("Missing %s", missing.name),
+			fmt.Sprintf("%s is set by %s, but %q is not set. Set the %q argument in the provider configuration or the %s environment variable.",
+				present.name, present.describe(), missing.name, missing.name, missing.envVar))
+	}
+	insecureSkipVerify := parseBoolSetting(resolveBoolSetting("insecure_skip_verify", data.InsecureSkipVerify, envInsecureSkipVerify), false, &resp.Diagnostics)
+	transportConfig := vstack_api.TransportConfig{
+		ConnectTimeout:     connectTimeout,
+		ReadTimeout:        readTimeout,
+		CACertFile:         resolveSetting("ca_cert_file", data.CACertFile, envCACertFile).value,
+		CACertPEM:          resolveSetting("ca_cert_pem", data.CACertPEM, envCACertPEM).value,
+		ClientCert:         clientCert.value,
+		ClientKey:          clientKey.value,
+		InsecureSkipVerify: insecureSkipVerify,
+		ProxyURL:           resolveSetting("proxy_url", data.ProxyURL, envProxyURL).value,
 	}
 
-	// Check authentication result code
-	if authResp.Result.Code != 1 {
-		resp.Diagnostics.AddError("Authentication failed", authResp.Result.Message)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Store the received auth cookie
-	p.authCookie = authResp.Result.Data.Cookie["APIEndpoint00"]
+	// Initialize the API client
+	if transportConfig.InsecureSkipVerify {
+		tflog.Warn(ctx, "TLS certificate verification of the vStack API is disabled by insecure_skip_verify")
+	}
+	httpClient, err := vstack_api.NewHTTPClient(transportConfig)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating HTTP client",
+			fmt.Sprintf("Failed to apply the TLS or proxy settings: %s", err))
+		return
+	}
+	p.client = vstack_api.NewClient(strings.TrimRight(host.value, "/"), httpClient)
+	p.client.SetRetryPolicy(retryPolicy)
 
-	// Retrieve cookie from response headers as a fallback
-	for _, cookie := range authResponse.Cookies() {
-		if cookie.Name == "APIEndpoint00" {
-			p.authCookie = cookie.Value
-			break
+	// Initialize the VM lock backend shared with other provider processes
+	switch lockBackendName {
+	case lockBackendFile:
+		backend, err := helper.NewFileLockBackend(lockDir.value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root(lockDir.name), "Error creating lock directory",
+				fmt.Sprintf("Failed to initialize the %q lock backend: %s", lockBackendFile, err))
+			return
 		}
+		p.lockConfig.Backend = backend
+	case lockBackendLease:
+		p.lockConfig.Backend = helper.NewLeaseLockBackend(p.client, helper.DefaultLeaseTTL)
 	}
-	if p.authCookie == "" {
-		resp.Diagnostics.AddError("Authentication failed", "No auth cookie received")
+
+	// Authenticate and store the session in the client
+	err = p.client.Auth(ctx, vstack_api.AuthParams{
+		Username: username.value,
+		Password: password.value,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Authentication failed",
+			fmt.Sprintf("Authentication with username from %s failed: %s", username.describe(), err))
 		return
 	}
 
-	tflog.Info(ctx, "Provider configuration completed", map[string]any{"provider": p})
+	tflog.Info(ctx, "Provider configuration completed", map[string]any{
+		"host":            host.value,
+		"host_source":     host.describe(),
+		"username":        username.value,
+		"username_source": username.describe(),
+		"max_retries":     retryPolicy.MaxRetries,
+		"status_timeout":  statusTimeout.String(),
+		"lock_backend":    lockBackendName,
+		"lock_timeout":    p.lockConfig.Timeout.String(),
+	})
 	// Pass the provider instance to be used in DataSources and Resources
 	resp.DataSourceData = p
 	resp.ResourceData = p
-	fmt.Println("Provider successfully configured")
+}
+
+// resolveBoolSetting returns the configured value of a bool argument, falling back to envVar
+// when the argument is null.
+func resolveBoolSetting(name string, value types.Bool, envVar string) providerSetting {
+	stringValue := types.StringNull()
+	if !value.IsNull() && !value.IsUnknown() {
+		stringValue = types.StringValue(strconv.FormatBool(value.ValueBool()))
+	}
+	return resolveSetting(name, stringValue, envVar)
+}
+
+// parseBoolSetting parses an optional bool setting of the provider, returning defaultValue if it is not set.
+// Invalid values are reported in diags together with their origin.
+func parseBoolSetting(setting providerSetting, defaultValue bool, diags *diag.Diagnostics) bool {
+	if !setting.isSet() {
+		return defaultValue
+	}
+
+	b, err := strconv.ParseBool(setting.value)
+	if err != nil {
+		diags.AddAttributeError(path.Root(setting.name), fmt.Sprintf("Invalid %s", setting.name),
+			fmt.Sprintf("Invalid value %q from %s: must be a boolean", setting.value, setting.describe()))
+		return defaultValue
+	}
+	return b
+}
+
+// validateHost checks that the host is an absolute http(s) URL.
+func validateHost(host string) error {
+	u, err := url.Parse(host)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an absolute URL starting with http:// or https://, e.g. https://vstack.example.com")
+	}
+	return nil
+}
+
+// parseDurationSetting parses an optional duration setting of the provider.
+// An unset value yields zero, which means that the default is used. Invalid values are reported
+// in diags together with their origin.
+func parseDurationSetting(setting providerSetting, diags *diag.Diagnostics) time.Duration {
+	if !setting.isSet() {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(setting.value)
+	if err == nil && duration <= 0 {
+		err = fmt.Errorf("duration must be positive")
+	}
+	if err != nil {
+		diags.AddAttributeError(path.Root(setting.name), fmt.Sprintf("Invalid %s", setting.name),
+			fmt.Sprintf("Invalid value %q from %s: %s", setting.value, setting.describe(), err))
+		return 0
+	}
+
+	return duration
 }
 
 // Resources returns a list of resource constructors for the provider.
@@ -173,6 +499,8 @@ func (p *VStackProvider) Resources(ctx context.Context) []func() resource.Resour
 	return []func() resource.Resource{
 		NewVstackVMResource,
 		NewVstackNicResource,
+		NewVstackNetworkResource,
+		NewVstackVMSnapshotResource,
 	}
 }
 
@@ -182,6 +510,9 @@ func (p *VStackProvider) DataSources(ctx context.Context) []func() datasource.Da
 		// NewExampleDataSource, // Uncomment if you have additional data sources
 		NewVstackVMGetDataSource,
 		NewVstackVMProfileDataSource,
+		NewVstackNetworkDataSource,
+		NewVstackVMsDataSource,
+		NewVstackVMSnapshotsDataSource,
 	}
 }
 
@@ -4,11 +4,16 @@
 package provider
 
 import (
+	"flag"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/providerserver"
-	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"os"
+	"strconv"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	"terraform-provider-vstack/internal/vstacktest"
 )
 
 // providerConfigTemplate is a template for the provider configuration using environment variables.
@@ -66,29 +71,67 @@ var (
 	}
 )
 
-// TestMain is the entry point for all tests in this package. It validates that
-// all required environment variables are present. If any are missing, the tests
-// will fail immediately (exit code 1).
+// mockAPI runs the acceptance tests against the in-process fake of the vStack API
+// instead of a real cluster, e.g. "TF_ACC=1 go test ./internal/provider -vstack.mock".
+var mockAPI = flag.Bool("vstack.mock", false, "run the acceptance tests against an in-process fake of the vStack API")
+
+// mockServer is the fake vStack API started with -vstack.mock, nil otherwise.
+var mockServer *vstacktest.Server
+
+// skipWithoutMock skips a test step that controls the fake vStack API when the tests run against a real cluster.
+func skipWithoutMock() (bool, error) {
+	return mockServer == nil, nil
+}
+
+// TestMain is the entry point for all tests in this package.
+// With -vstack.mock it starts the fake vStack API and points the TF_VAR_* variables to it.
+// Otherwise, when acceptance tests are enabled with TF_ACC, it validates that all required
+// environment variables are present; if any are missing, the tests fail immediately (exit code 1).
 func TestMain(m *testing.M) {
-	requiredVars := []string{
-		"TF_VAR_host",
-		"TF_VAR_username",
-		"TF_VAR_password",
-		"TF_VAR_vdc_id",
-		"TF_VAR_network_id",
-	}
+	flag.Parse()
 
-	var missingVars []string
-	for _, v := range requiredVars {
-		if os.Getenv(v) == "" {
-			missingVars = append(missingVars, v)
+	if *mockAPI {
+		server := vstacktest.NewServer()
+		mockServer = server
+		for name, value := range map[string]string{
+			"TF_VAR_host":       server.URL,
+			"TF_VAR_username":   server.Username,
+			"TF_VAR_password":   server.Password,
+			"TF_VAR_vdc_id":     strconv.Itoa(vstacktest.DefaultVdcID),
+			"TF_VAR_network_id": strconv.Itoa(vstacktest.DefaultNetworkID),
+		} {
+			if err := os.Setenv(name, value); err != nil {
+				fmt.Printf("ERROR: Failed to set %s: %v\n", name, err)
+				os.Exit(1)
+			}
 		}
+
+		code := m.Run()
+		server.Close()
+		os.Exit(code)
 	}
 
-	if len(missingVars) > 0 {
-		fmt.Printf("ERROR: Missing required environment variables: %v\n", missingVars)
-		// Approach #1: Exit with a non-zero status, marking tests as failed.
-		os.Exit(1)
+	if os.Getenv("TF_ACC") != "" {
+		requiredVars := []string{
+			"TF_VAR_host",
+			"TF_VAR_username",
+			"TF_VAR_password",
+			"TF_VAR_vdc_id",
+			"TF_VAR_network_id",
+		}
+
+		var missingVars []string
+		for _, v := range requiredVars {
+			if os.Getenv(v) == "" {
+				missingVars = append(missingVars, v)
+			}
+		}
+
+		if len(missingVars) > 0 {
+			fmt.Printf("ERROR: Missing required environment variables: %v\n", missingVars)
+			// Approach #1: Exit with a non-zero status, marking tests as failed.
+			os.Exit(1)
+		}
 	}
 
 	// If all variables are set, proceed with the tests.
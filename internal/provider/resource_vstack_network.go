@@ -0,0 +1,423 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/timeouts"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// Default timeouts of the network resource operations.
+const (
+	defaultNetworkCreateTimeout = 5 * time.Minute
+	defaultNetworkReadTimeout   = 5 * time.Minute
+	defaultNetworkUpdateTimeout = 5 * time.Minute
+	defaultNetworkDeleteTimeout = 5 * time.Minute
+)
+
+// VstackNetworkResource is the resource responsible for managing a VLAN or VXLAN network of a VDC.
+type VstackNetworkResource struct {
+	Client *vstack_api.Client
+}
+
+// Ensure VstackNetworkResource satisfies the Terraform interfaces.
+var (
+	_ resource.Resource                = &VstackNetworkResource{}
+	_ resource.ResourceWithConfigure   = &VstackNetworkResource{}
+	_ resource.ResourceWithImportState = &VstackNetworkResource{}
+)
+
+// NewVstackNetworkResource initializes the network resource.
+func NewVstackNetworkResource() resource.Resource {
+	return &VstackNetworkResource{}
+}
+
+// Metadata sets the name of the resource.
+func (r *VstackNetworkResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network"
+}
+
+// Schema defines the schema for the network resource.
+func (r *VstackNetworkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a VLAN or VXLAN network of a vStack VDC.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Description: "Unique identifier of the network. Use it as network_id of vstack_nic.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the network.",
+				Required:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "Description of the network.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+			},
+			"vdc_id": schema.Int64Attribute{
+				Description: "Virtual Data Center ID owning the network. Changing it replaces the network.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Description: "Network type: vlan or vxlan. Changing it replaces the network.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringOneOf(vstack_api.NetworkTypeVLAN, vstack_api.NetworkTypeVXLAN),
+				},
+			},
+			"tag": schema.Int64Attribute{
+				Description: "VLAN ID or VXLAN network identifier (VNI). If not provided, vStack allocates a free one. " +
+					"Changing it replaces the network.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"cidr": schema.StringAttribute{
+				Description: "Subnet of the network in CIDR notation, e.g. 10.0.0.0/24. Changing it replaces the network.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"gateway": schema.StringAttribute{
+				Description: "Default gateway address of the network. Empty if the network has no gateway.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+			},
+			"dhcp": schema.SingleNestedAttribute{
+				Description: "DHCP settings of the network. DHCP is disabled if not provided.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"enabled": schema.BoolAttribute{
+						Description: "Whether DHCP is enabled. Defaults to true.",
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(true),
+					},
+					"range_start": schema.StringAttribute{
+						Description: "First address leased by DHCP.",
+						Optional:    true,
+					},
+					"range_end": schema.StringAttribute{
+						Description: "Last address leased by DHCP.",
+						Optional:    true,
+					},
+					"dns_servers": schema.ListAttribute{
+						Description: "DNS servers announced by DHCP.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Configure sets up the resource with provider data.
+func (r *VstackNetworkResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	if pd, ok := req.ProviderData.(*VStackProvider); ok {
+		r.Client = pd.client
+	} else {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data Type",
+			"The provider data was not of the expected *VStackProvider type.",
+		)
+	}
+}
+
+// Create creates the network in the VDC.
+func (r *VstackNetworkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemNetwork)
+
+	var plan models.NetworkModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Bound the API calls of the operation by the create timeout
+	createTimeout, diags := timeouts.Create(ctx, plan.Timeouts, defaultNetworkCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	// 1. Build the request to create the network
+	params := vstack_api.NetworksCreateParams{
+		VdcID:       plan.VdcID.ValueInt64(),
+		Name:        plan.Name.ValueString(),
+		Description: plan.Description.ValueString(),
+		Type:        plan.Type.ValueString(),
+		Tag:         plan.Tag.ValueInt64(),
+		CIDR:        plan.CIDR.ValueString(),
+		Gateway:     plan.Gateway.ValueString(),
+		DHCP:        helper.NetworkDHCPParams(plan.DHCP),
+	}
+
+	// 2. Create the network
+	createResp, err := r.Client.NetworksCreate(ctx, params)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating network", err.Error())
+		return
+	}
+
+	// 3. Map the created network to the state
+	mapNetworkToModel(createResp.Data, &plan)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Log successful network creation
+	tflog.SubsystemInfo(ctx, logSubsystemNetwork, "Created network", map[string]any{"network_id": createResp.Data.ID, "vdc_id": params.VdcID})
+}
+
+// Read retrieves the current state of the network from vStack and updates the Terraform state.
+func (r *VstackNetworkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemNetwork)
+
+	var state models.NetworkModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Bound the API calls of the operation by the read timeout
+	readTimeout, diags := timeouts.Read(ctx, state.Timeouts, defaultNetworkReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	networkID := state.ID.ValueInt64()
+	if networkID == 0 {
+		resp.Diagnostics.AddError("Invalid network ID", "Network ID must be greater than zero.")
+		return
+	}
+
+	// Retrieve the network. A network that no longer exists is removed from the state;
+	// other errors, e.g. a held lock or a lost connection, fail the read.
+	getResp, err := r.Client.NetworkGet(ctx, vstack_api.NetworkGetParams{ID: networkID})
+	if err != nil {
+		if vstack_api.IsNotFound(err) {
+			tflog.SubsystemWarn(ctx, logSubsystemNetwork, "Network not found, removing it from state", map[string]any{"network_id": networkID, "error": err.Error()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error retrieving network", err.Error())
+		return
+	}
+
+	mapNetworkToModel(getResp.Data, &state)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Log successful network state read
+	tflog.SubsystemDebug(ctx, logSubsystemNetwork, "Read network state", map[string]any{"network_id": networkID})
+}
+
+// Update changes the name, description, gateway and DHCP settings of the network.
+// The other attributes require replacing the network.
+func (r *VstackNetworkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemNetwork)
+
+	// Retrieve the desired plan and the current state
+	var plan, state models.NetworkModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Bound the API calls of the operation by the update timeout
+	updateTimeout, diags := timeouts.Update(ctx, plan.Timeouts, defaultNetworkUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	networkID := state.ID.ValueInt64()
+
+	// 1. Collect the changed network parameters
+	var params vstack_api.NetworkSetNetworkParams
+	if !plan.Name.Equal(state.Name) {
+		params.Name = plan.Name.ValueStringPointer()
+	}
+	if !plan.Description.Equal(state.Description) {
+		params.Description = plan.Description.ValueStringPointer()
+	}
+	if !plan.Gateway.Equal(state.Gateway) {
+		params.Gateway = plan.Gateway.ValueStringPointer()
+	}
+	planDHCP := helper.NetworkDHCPParams(plan.DHCP)
+	if !networkDHCPEqual(planDHCP, helper.NetworkDHCPParams(state.DHCP)) {
+		params.DHCP = &planDHCP
+	}
+
+	// 2. Send the changes, if any
+	if params != (vstack_api.NetworkSetNetworkParams{}) {
+		if _, err := r.Client.NetworkSet(ctx, vstack_api.NetworkSetParams{ID: networkID, NetworkParams: params}); err != nil {
+			resp.Diagnostics.AddError("Error updating network", err.Error())
+			return
+		}
+	}
+
+	// 3. Retrieve the updated network to set the state
+	getResp, err := r.Client.NetworkGet(ctx, vstack_api.NetworkGetParams{ID: networkID})
+	if err != nil {
+		resp.Diagnostics.AddError("Error retrieving network", err.Error())
+		return
+	}
+	plan.ID = state.ID
+	mapNetworkToModel(getResp.Data, &plan)
+
+	// 4. Set the updated state
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Log successful network update
+	tflog.SubsystemInfo(ctx, logSubsystemNetwork, "Updated network", map[string]any{"network_id": networkID})
+}
+
+// Delete removes the network. vStack rejects it while NICs are still attached to the network.
+func (r *VstackNetworkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemNetwork)
+
+	var state models.NetworkModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Bound the API calls of the operation by the delete timeout
+	deleteTimeout, diags := timeouts.Delete(ctx, state.Timeouts, defaultNetworkDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	networkID := state.ID.ValueInt64()
+
+	// 1. Remove the network
+	if _, err := r.Client.NetworksRemove(ctx, vstack_api.NetworksRemoveParams{ID: networkID}); err != nil {
+		resp.Diagnostics.AddError("Error deleting network", err.Error())
+		return
+	}
+
+	// 2. Remove the resource from Terraform state
+	resp.State.RemoveResource(ctx)
+
+	// Log successful network deletion
+	tflog.SubsystemInfo(ctx, logSubsystemNetwork, "Deleted network", map[string]any{"network_id": networkID})
+}
+
+// ImportState imports a network by its ID.
+func (r *VstackNetworkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	networkID, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Unable to parse network ID '%s': %s", req.ID, err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), networkID)...)
+}
+
+// mapNetworkToModel copies the attributes of the network returned by vStack into the resource model.
+func mapNetworkToModel(network vstack_api.Network, model *models.NetworkModel) {
+	model.ID = types.Int64Value(network.ID)
+	model.Name = types.StringValue(network.Name)
+	model.Description = types.StringValue(network.Description)
+	model.VdcID = types.Int64Value(network.VdcID)
+	model.Type = types.StringValue(network.Type)
+	model.Tag = types.Int64Value(network.Tag)
+	model.CIDR = types.StringValue(network.CIDR)
+	model.Gateway = types.StringValue(network.Gateway)
+	model.DHCP = helper.MapNetworkDHCP(network.DHCP, model.DHCP)
+}
+
+// networkDHCPEqual reports whether two DHCP settings are the same.
+func networkDHCPEqual(a, b vstack_api.NetworkDHCP) bool {
+	if a.Enabled != b.Enabled || a.RangeStart != b.RangeStart || a.RangeEnd != b.RangeEnd || len(a.DNSServers) != len(b.DNSServers) {
+		return false
+	}
+	for i := range a.DNSServers {
+		if a.DNSServers[i] != b.DNSServers[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccVStackNetwork tests the vstack_network resource and looks the created network up with the data source.
+func TestAccVStackNetwork(t *testing.T) {
+	resourceConfigTemplate := `
+resource "vstack_network" "test" {
+  name   = "tf-acc-network"
+  vdc_id = var.vdc_id
+  type   = "vxlan"
+  cidr   = "192.168.50.0/24"
+
+  dhcp = {
+    range_start = "192.168.50.100"
+    range_end   = "192.168.50.200"
+  }
+}
+
+data "vstack_network" "test" {
+  name   = vstack_network.test.name
+  vdc_id = vstack_network.test.vdc_id
+}
+`
+
+	// The gateway, description and DHCP settings are changed in place.
+	resourceConfigUpdateTemplate := `
+resource "vstack_network" "test" {
+  name        = "tf-acc-network"
+  description = "Managed by Terraform"
+  vdc_id      = var.vdc_id
+  type        = "vxlan"
+  cidr        = "192.168.50.0/24"
+  gateway     = "192.168.50.1"
+
+  dhcp = {
+    range_start = "192.168.50.100"
+    range_end   = "192.168.50.150"
+    dns_servers = ["192.168.50.1"]
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfigTemplate + resourceConfigTemplate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("vstack_network.test", "id"),
+					resource.TestCheckResourceAttrSet("vstack_network.test", "tag"),
+					resource.TestCheckResourceAttr("vstack_network.test", "dhcp.enabled", "true"),
+					resource.TestCheckResourceAttr("vstack_network.test", "gateway", ""),
+
+					// The data source resolves the network by name within the VDC.
+					resource.TestCheckResourceAttrPair("data.vstack_network.test", "id", "vstack_network.test", "id"),
+					resource.TestCheckResourceAttrPair("data.vstack_network.test", "tag", "vstack_network.test", "tag"),
+					resource.TestCheckResourceAttr("data.vstack_network.test", "cidr", "192.168.50.0/24"),
+				),
+			},
+			{
+				Config: providerConfigTemplate + resourceConfigUpdateTemplate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_network.test", "description", "Managed by Terraform"),
+					resource.TestCheckResourceAttr("vstack_network.test", "gateway", "192.168.50.1"),
+					resource.TestCheckResourceAttr("vstack_network.test", "dhcp.range_end", "192.168.50.150"),
+					resource.TestCheckResourceAttr("vstack_network.test", "dhcp.dns_servers.0", "192.168.50.1"),
+				),
+			},
+			{
+				ResourceName:      "vstack_network.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"timeouts",
+				},
+			},
+		},
+	})
+}
@@ -5,28 +5,41 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"log"
-	"net/http"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"strconv"
 	"strings"
 	"terraform-provider-vstack/internal/helper"
 	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/timeouts"
 	"terraform-provider-vstack/internal/vstack_api"
+	"time"
+)
+
+// Default timeouts of the NIC resource operations. They include stopping and starting the VM if needed.
+const (
+	defaultNicCreateTimeout = 15 * time.Minute
+	defaultNicReadTimeout   = 5 * time.Minute
+	defaultNicUpdateTimeout = 15 * time.Minute
+	defaultNicDeleteTimeout = 15 * time.Minute
 )
 
 // VstackNicResource is the resource responsible for managing a single NIC.
 type VstackNicResource struct {
-	Client     *http.Client
-	BaseURL    string
-	AuthCookie string
+	Client        *vstack_api.Client
+	StatusTimeout time.Duration
+	LockConfig    helper.VMLockConfig
+	Hotplug       bool // The provider default of the hotplug attribute.
 }
 
 func NewVstackNicResource() resource.Resource {
@@ -38,11 +51,10 @@ func (r *VstackNicResource) Schema(ctx context.Context, req resource.SchemaReque
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
-				Description: "Unique identifier of the NIC (port_id).",
+				Description: "Unique identifier of the NIC (port_id). It changes when network_id, address or ip_guard change.",
 				Computed:    true,
 				PlanModifiers: []planmodifier.Int64{
-					int64planmodifier.UseStateForUnknown(),
-					int64planmodifier.RequiresReplace(),
+					useStateUnlessNicReattached{},
 				},
 			},
 			"vm_id": schema.Int64Attribute{
@@ -54,11 +66,8 @@ func (r *VstackNicResource) Schema(ctx context.Context, req resource.SchemaReque
 				},
 			},
 			"network_id": schema.Int64Attribute{
-				Description: "Network ID.",
+				Description: "Network ID. Changing it re-attaches the NIC, keeping its MAC address and slot.",
 				Required:    true,
-				PlanModifiers: []planmodifier.Int64{
-					int64planmodifier.RequiresReplace(),
-				},
 			},
 			"slot": schema.Int64Attribute{
 				Description: "Slot number for the NIC.",
@@ -68,12 +77,11 @@ func (r *VstackNicResource) Schema(ctx context.Context, req resource.SchemaReque
 				},
 			},
 			"address": schema.StringAttribute{
-				Description: "IP address of the NIC. If not provided, it may be auto-assigned by vStack.",
+				Description: "IP address of the NIC. If not provided, it may be auto-assigned by vStack. Changing it re-attaches the NIC, keeping its MAC address and slot.",
 				Optional:    true,
 				Computed:    true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.UseStateForUnknown(),
-					stringplanmodifier.RequiresReplace(),
+					useStateUnlessNicReattached{},
 				},
 			},
 			"mac": schema.StringAttribute{
@@ -81,22 +89,47 @@ func (r *VstackNicResource) Schema(ctx context.Context, req resource.SchemaReque
 				Computed:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
-					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"ratelimit_mbits": schema.Int64Attribute{
 				Description: "Rate limit in Mbps for the NIC.",
 				Optional:    true,
 				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"ip_guard": schema.Int64Attribute{
-				Description: "IP guard setting for the NIC.",
+				Description: "IP guard setting for the NIC. Changing it re-attaches the NIC, keeping its MAC address and slot.",
+				Optional:    true,
 				Computed:    true,
 				PlanModifiers: []planmodifier.Int64{
-					int64planmodifier.RequiresReplace(),
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"hotplug": schema.BoolAttribute{
+				Description: "Whether to attach and detach the NIC while the VM is running. Overrides the nic_hotplug provider argument.",
+				Optional:    true,
+			},
+			"restart_policy": schema.StringAttribute{
+				Description: "Whether the VM may be stopped and started again to attach or detach the NIC: " +
+					"never (fail if the NIC cannot be hot-plugged), if_needed (only if vStack rejects the hot-plug) " +
+					"or always (always stop a running VM). Defaults to if_needed.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(helper.RestartPolicyIfNeeded),
+				Validators: []validator.String{
+					stringOneOf(helper.RestartPolicies...),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -112,8 +145,9 @@ func (r *VstackNicResource) Configure(ctx context.Context, req resource.Configur
 	}
 	if pd, ok := req.ProviderData.(*VStackProvider); ok {
 		r.Client = pd.client
-		r.AuthCookie = pd.authCookie
-		r.BaseURL = pd.Host
+		r.StatusTimeout = pd.statusTimeout
+		r.LockConfig = pd.lockConfig
+		r.Hotplug = pd.nicHotplug
 	} else {
 		resp.Diagnostics.AddError(
 			"Unexpected Provider Data Type",
@@ -124,6 +158,7 @@ func (r *VstackNicResource) Configure(ctx context.Context, req resource.Configur
 
 // Create adds a NIC to the VM and ensures the VM is in a stable state.
 func (r *VstackNicResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemNIC)
 
 	var plan models.NetworkPortModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -132,6 +167,15 @@ func (r *VstackNicResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	// Bound the API calls and status polling of the operation by the create timeout
+	createTimeout, diags := timeouts.Create(ctx, plan.Timeouts, defaultNicCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	vmID := plan.VmID.ValueInt64()
 	if vmID == 0 {
 		resp.Diagnostics.AddError("Invalid VM ID", "VM ID must be greater than zero.")
@@ -139,66 +183,45 @@ func (r *VstackNicResource) Create(ctx context.Context, req resource.CreateReque
 	}
 
 	// Retrieve the mutex for the VM and lock it
-	mu, err := helper.GetVMLock(vmID)
-	if err != nil {
-		resp.Diagnostics.AddError("Error on GetVMLock", err.Error())
-		return
-	}
-	mu.Lock()
-	defer mu.Unlock()
-
-	// 1. Check if the VM was running
-	wasRunning, err := helper.CheckIfVMIsRunning(r.Client, r.AuthCookie, r.BaseURL, vmID)
+	unlock, err := helper.GetVMLock(ctx, r.LockConfig, vmID, "vstack_nic create")
 	if err != nil {
-		resp.Diagnostics.AddError("Error checking VM status", err.Error())
+		resp.Diagnostics.AddError("Error acquiring VM lock", err.Error())
 		return
 	}
+	defer unlock()
 
-	// 2. Stop the VM if it was running
-	if wasRunning {
-		if err := helper.PerformAction(r.Client, r.AuthCookie, r.BaseURL, vmID, "stop"); err != nil {
-			resp.Diagnostics.AddError("Error stopping VM before adding NIC", err.Error())
-			return
-		}
-	}
-
-	// 3. Build the request to add NIC
-	params := map[string]interface{}{
-		"id":         vmID,
-		"network_id": plan.NetworkID.ValueInt64(),
-		"slot":       plan.Slot.ValueInt64(),
+	// 1. Build the request to add NIC
+	params := vstack_api.VmsAddNicParams{
+		ID:        vmID,
+		NetworkID: plan.NetworkID.ValueInt64(),
+		Slot:      plan.Slot.ValueInt64(),
 	}
 
 	if !plan.RatelimitMbits.IsNull() {
-		params["ratelimit_mbits"] = plan.RatelimitMbits.ValueInt64()
+		params.RatelimitMBits = plan.RatelimitMbits.ValueInt64Pointer()
 	}
 
-	if !plan.Address.IsNull() && plan.Address.ValueString() != "" {
-		params["address"] = plan.Address.ValueString()
+	if !plan.Address.IsNull() {
+		params.Address = plan.Address.ValueString()
 	}
 
-	if !plan.IpGuard.IsNull() && plan.IpGuard.ValueInt64() != 0 {
-		params["ip_guard"] = plan.IpGuard.ValueInt64()
+	if !plan.IpGuard.IsNull() && !plan.IpGuard.IsUnknown() {
+		params.IPGuard = plan.IpGuard.ValueInt64()
 	}
 
-	requestCreatePayload := helper.BuildJSONRPCRequest("vms-add-nic", params)
-
-	// 4. Call the API to add NIC
-	addResp, addErr := vstack_api.VmsAddNic(requestCreatePayload, r.AuthCookie, r.BaseURL, r.Client)
-	if addErr != nil {
-		resp.Diagnostics.AddError("Error adding NIC", addErr.Error())
+	// 2. Add the NIC, hot-plugging it or stopping the VM according to the restart policy
+	var addResp vstack_api.VmsAddNicResult
+	restarted, err := helper.ApplyHardwareChange(ctx, r.Client, vmID, r.hotplugOptions(plan), func(ctx context.Context) error {
+		var addErr error
+		addResp, addErr = r.Client.VmsAddNic(ctx, params)
+		return addErr
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error adding NIC", err.Error())
 		return
 	}
 
-	// 5. Restart the VM if it was running
-	if wasRunning {
-		if err := helper.PerformAction(r.Client, r.AuthCookie, r.BaseURL, vmID, "start"); err != nil {
-			resp.Diagnostics.AddError("Error restarting VM after adding NIC", err.Error())
-			return
-		}
-	}
-
-	// 6. Update the state with the added NIC details
+	// 3. Update the state with the added NIC details
 	plan.ID = types.Int64Value(addResp.Data.PortID)
 	plan.MAC = types.StringValue(addResp.Data.MAC)
 
@@ -222,11 +245,13 @@ func (r *VstackNicResource) Create(ctx context.Context, req resource.CreateReque
 	}
 
 	// Log successful NIC addition
-	log.Printf("Successfully added NIC with ID %d to VM ID %d", addResp.Data.PortID, vmID)
+	tflog.SubsystemInfo(ctx, logSubsystemNIC, "Added NIC", map[string]any{"vm_id": vmID, "port_id": addResp.Data.PortID, "vm_restarted": restarted})
 }
 
 // Read retrieves the current state of the NIC from vStack and updates the Terraform state.
 func (r *VstackNicResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemNIC)
+
 	var state models.NetworkPortModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -234,6 +259,15 @@ func (r *VstackNicResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	// Bound the API calls and status polling of the operation by the read timeout
+	readTimeout, diags := timeouts.Read(ctx, state.Timeouts, defaultNicReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	vmID := state.VmID.ValueInt64()
 	portID := state.ID.ValueInt64()
 
@@ -243,10 +277,15 @@ func (r *VstackNicResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	// Call vm-get to retrieve VM details and find the NIC
-	nic, err := helper.FindNicInVmGet(r.Client, r.AuthCookie, r.BaseURL, vmID, portID)
+	nic, err := helper.FindNicInVmGet(ctx, r.Client, vmID, portID)
 	if err != nil {
-		// If NIC not found, remove the resource from state
-		resp.State.RemoveResource(ctx)
+		// Only a NIC or VM that is gone is removed from the state
+		if errors.Is(err, helper.ErrNicNotFound) || vstack_api.IsNotFound(err) {
+			tflog.SubsystemWarn(ctx, logSubsystemNIC, "NIC not found, removing it from state", map[string]any{"vm_id": vmID, "port_id": portID, "error": err.Error()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error retrieving NIC", err.Error())
 		return
 	}
 
@@ -272,26 +311,44 @@ func (r *VstackNicResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	// Log successful NIC state read
-	log.Printf("Successfully read NIC state for NIC ID %d", portID)
+	tflog.SubsystemDebug(ctx, logSubsystemNIC, "Read NIC state", map[string]any{"vm_id": vmID, "port_id": portID})
 }
 
 // Update modifies the NIC parameters as needed.
+// The rate limit is changed on the attached port; network_id, address and ip_guard are changed by
+// re-attaching the NIC with the same MAC address and slot, which assigns it a new port ID.
 func (r *VstackNicResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Retrieve the desired plan and current state
-	var plan, state models.NetworkPortModel
+	ctx = withLogSubsystem(ctx, logSubsystemNIC)
+
+	// Retrieve the desired plan, the configuration and the current state
+	var plan, config, state models.NetworkPortModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	diags = req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	vmID := plan.VmID.ValueInt64()
-	nicID := plan.ID.ValueInt64()
+	// Bound the API calls and status polling of the operation by the update timeout
+	updateTimeout, diags := timeouts.Update(ctx, plan.Timeouts, defaultNicUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	vmID := state.VmID.ValueInt64()
+	nicID := state.ID.ValueInt64()
 
 	if vmID == 0 || nicID == 0 {
 		resp.Diagnostics.AddError("Invalid IDs", "VM ID and NIC ID must be greater than zero.")
@@ -299,52 +356,93 @@ func (r *VstackNicResource) Update(ctx context.Context, req resource.UpdateReque
 	}
 
 	// Lock the VM to prevent concurrent operations
-	mu, err := helper.GetVMLock(vmID)
+	unlock, err := helper.GetVMLock(ctx, r.LockConfig, vmID, "vstack_nic update")
 	if err != nil {
-		resp.Diagnostics.AddError("Error on GetVMLock", err.Error())
+		resp.Diagnostics.AddError("Error acquiring VM lock", err.Error())
 		return
 	}
-	mu.Lock()
-	defer mu.Unlock()
-
-	// 1. Collect changed NIC parameters (excluding those that require replacement)
-	nicParams := make(map[string]interface{})
+	defer unlock()
 
-	// Only handle ratelimit_mbits as per your requirements
-	if plan.RatelimitMbits.ValueInt64() != state.RatelimitMbits.ValueInt64() {
-		nicParams["ratelimit_mbits"] = plan.RatelimitMbits.ValueInt64()
-	}
+	// 1. Re-attach the NIC if network_id, address or ip_guard change
+	if nicReattachNeeded(config, state) {
+		old, err := helper.FindNicInVmGet(ctx, r.Client, vmID, nicID)
+		if err != nil {
+			resp.Diagnostics.AddError("Error retrieving NIC details", err.Error())
+			return
+		}
 
-	// Note: 'address' changes require replacement as per the schema's PlanModifiers
-	// So, if 'address' changes, Terraform will handle resource replacement, and no update is needed here
+		params := vstack_api.VmsAddNicParams{
+			NetworkID:      plan.NetworkID.ValueInt64(),
+			RatelimitMBits: old.RatelimitMBits,
+			IPGuard:        old.IPGuard,
+		}
+		if !plan.RatelimitMbits.IsNull() && !plan.RatelimitMbits.IsUnknown() {
+			params.RatelimitMBits = plan.RatelimitMbits.ValueInt64Pointer()
+		}
+		if !config.IpGuard.IsNull() {
+			params.IPGuard = config.IpGuard.ValueInt64()
+		}
+		switch {
+		case !config.Address.IsNull():
+			params.Address = config.Address.ValueString()
+		case old.NetworkID == params.NetworkID:
+			// Keep the auto-assigned address within the same network
+			params.Address = old.Address
+		}
 
-	// 2. Update NIC parameters if there are changes
-	if len(nicParams) > 0 {
-		// Use the setNicRatelimit helper function
-		newRate, exists := nicParams["ratelimit_mbits"].(int64)
-		if exists {
-			if err := helper.SetNicRatelimit(r.Client, r.AuthCookie, r.BaseURL, vmID, nicID, newRate); err != nil {
-				resp.Diagnostics.AddError("Error updating NIC ratelimit", err.Error())
-				return
+		var addResp vstack_api.VmsAddNicResult
+		restarted, err := helper.ApplyHardwareChange(ctx, r.Client, vmID, r.hotplugOptions(plan), func(ctx context.Context) error {
+			var reattachErr error
+			addResp, reattachErr = helper.ReattachNic(ctx, r.Client, vmID, old, params)
+			return reattachErr
+		})
+		if err != nil {
+			// The NIC restored with its old settings is a new port; keep tracking it
+			if addResp.Data.PortID != 0 {
+				state.ID = types.Int64Value(addResp.Data.PortID)
+				resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 			}
+			resp.Diagnostics.AddError("Error re-attaching NIC", err.Error())
+			return
+		}
+		tflog.SubsystemInfo(ctx, logSubsystemNIC, "Re-attached NIC", map[string]any{
+			"vm_id":        vmID,
+			"old_port_id":  nicID,
+			"port_id":      addResp.Data.PortID,
+			"vm_restarted": restarted,
+		})
+		nicID = addResp.Data.PortID
+	} else if !plan.RatelimitMbits.IsNull() && !plan.RatelimitMbits.IsUnknown() &&
+		plan.RatelimitMbits.ValueInt64() != state.RatelimitMbits.ValueInt64() {
+		// 2. Otherwise update the rate limit of the attached port
+		if err := helper.SetNicRatelimit(ctx, r.Client, vmID, nicID, plan.RatelimitMbits.ValueInt64()); err != nil {
+			resp.Diagnostics.AddError("Error updating NIC ratelimit", err.Error())
+			return
 		}
 	}
 
 	// 3. Retrieve the full information of the NIC to set the state
-	// Using FindNicInVmGet
-	nic, err := helper.FindNicInVmGet(r.Client, r.AuthCookie, r.BaseURL, vmID, nicID)
+	nic, err := helper.FindNicInVmGet(ctx, r.Client, vmID, nicID)
 	if err != nil {
 		resp.Diagnostics.AddError("Error retrieving NIC details", err.Error())
 		return
 	}
 
 	// Map the API response to Terraform state
+	state.ID = types.Int64Value(nic.PortID)
 	state.Address = types.StringValue(nic.Address)
 	state.MAC = types.StringValue(nic.MAC)
 	state.IpGuard = types.Int64Value(nic.IPGuard)
-	state.RatelimitMbits = types.Int64Value(*nic.RatelimitMBits)
+	if nic.RatelimitMBits != nil {
+		state.RatelimitMbits = types.Int64Value(*nic.RatelimitMBits)
+	} else {
+		state.RatelimitMbits = types.Int64Value(0)
+	}
 	state.Slot = types.Int64Value(nic.Slot)
 	state.NetworkID = types.Int64Value(nic.NetworkID)
+	state.Hotplug = plan.Hotplug
+	state.RestartPolicy = plan.RestartPolicy
+	state.Timeouts = plan.Timeouts
 
 	// 4. Set the updated state
 	diags = resp.State.Set(ctx, &state)
@@ -354,11 +452,13 @@ func (r *VstackNicResource) Update(ctx context.Context, req resource.UpdateReque
 	}
 
 	// Log successful NIC update
-	log.Printf("Successfully updated NIC with ID %d", nicID)
+	tflog.SubsystemInfo(ctx, logSubsystemNIC, "Updated NIC", map[string]any{"port_id": nicID})
 }
 
 // Delete removes the NIC from the VM and ensures the VM is in a stable state.
 func (r *VstackNicResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemNIC)
+
 	var state models.NetworkPortModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -366,6 +466,15 @@ func (r *VstackNicResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
+	// Bound the API calls and status polling of the operation by the delete timeout
+	deleteTimeout, diags := timeouts.Delete(ctx, state.Timeouts, defaultNicDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	vmID := state.VmID.ValueInt64()
 	portID := state.ID.ValueInt64()
 
@@ -375,58 +484,51 @@ func (r *VstackNicResource) Delete(ctx context.Context, req resource.DeleteReque
 	}
 
 	// Lock the VM to prevent concurrent operations
-	mu, err := helper.GetVMLock(vmID)
+	unlock, err := helper.GetVMLock(ctx, r.LockConfig, vmID, "vstack_nic delete")
 	if err != nil {
-		resp.Diagnostics.AddError("Error on GetVMLock", err.Error())
+		resp.Diagnostics.AddError("Error acquiring VM lock", err.Error())
 		return
 	}
-	mu.Lock()
-	defer mu.Unlock()
+	defer unlock()
 
-	// 1. Check if the VM was running
-	wasRunning, err := helper.CheckIfVMIsRunning(r.Client, r.AuthCookie, r.BaseURL, vmID)
+	// 1. Remove the NIC, hot-unplugging it or stopping the VM according to the restart policy
+	restarted, err := helper.ApplyHardwareChange(ctx, r.Client, vmID, r.hotplugOptions(state), func(ctx context.Context) error {
+		_, removeErr := r.Client.VmRemoveNic(ctx, vstack_api.VmRemoveNicParams{
+			VmID:   vmID,
+			PortID: portID,
+		})
+		return removeErr
+	})
 	if err != nil {
-		resp.Diagnostics.AddError("Error checking VM status", err.Error())
+		resp.Diagnostics.AddError("Error deleting NIC", err.Error())
 		return
 	}
 
-	// 2. Stop the VM if it was running
-	if wasRunning {
-		if err := helper.PerformAction(r.Client, r.AuthCookie, r.BaseURL, vmID, "stop"); err != nil {
-			resp.Diagnostics.AddError("Error stopping VM before removing NIC", err.Error())
-			return
-		}
-	}
+	// 2. Remove the resource from Terraform state
+	resp.State.RemoveResource(ctx)
 
-	// 3. Remove the NIC
-	removeReq := helper.BuildJSONRPCRequest("vm-remove-nic", map[string]interface{}{
-		"vm_id":   vmID,
-		"port_id": portID,
-	})
+	// Log successful NIC deletion
+	tflog.SubsystemInfo(ctx, logSubsystemNIC, "Deleted NIC", map[string]any{"vm_id": vmID, "port_id": portID, "vm_restarted": restarted})
+}
 
-	_, removeErr := vstack_api.VmRemoveNic(removeReq, r.AuthCookie, r.BaseURL, r.Client)
-	if removeErr != nil {
-		resp.Diagnostics.AddError("Error deleting NIC", removeErr.Error())
-		return
+// hotplugOptions returns how a NIC is attached to or detached from a running VM.
+// The hotplug attribute overrides the nic_hotplug provider argument.
+func (r *VstackNicResource) hotplugOptions(model models.NetworkPortModel) helper.HotplugOptions {
+	hotplug := r.Hotplug
+	if !model.Hotplug.IsNull() && !model.Hotplug.IsUnknown() {
+		hotplug = model.Hotplug.ValueBool()
 	}
-
-	// 4. Restart the VM if it was running
-	if wasRunning {
-		if err := helper.PerformAction(r.Client, r.AuthCookie, r.BaseURL, vmID, "start"); err != nil {
-			resp.Diagnostics.AddError("Error restarting VM after removing NIC", err.Error())
-			return
-		}
+	return helper.HotplugOptions{
+		Hotplug:       hotplug,
+		RestartPolicy: model.RestartPolicy.ValueString(),
+		StatusTimeout: r.StatusTimeout,
 	}
-
-	// 5. Remove the resource from Terraform state
-	resp.State.RemoveResource(ctx)
-
-	// Log successful NIC deletion
-	log.Printf("Successfully deleted NIC with ID %d from VM ID %d", portID, vmID)
 }
 
 // ImportState handles importing a resource.
 func (r *VstackNicResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemNIC)
+
 	// Split the provided import ID into VM ID and Port ID
 	ids := strings.Split(req.ID, "/")
 	if len(ids) != 2 {
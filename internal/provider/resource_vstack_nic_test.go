@@ -13,6 +13,10 @@ import (
 
 // TestAccVStackNIC tests the VStack NIC resource, including Create, Update, and Delete steps.
 func TestAccVStackNIC(t *testing.T) {
+	if os.Getenv(resource.EnvTfAcc) == "" {
+		t.Skipf("Acceptance tests skipped unless env '%s' set", resource.EnvTfAcc)
+	}
+
 	// Retrieve the network ID from the environment variable.
 	networkIdStr := os.Getenv("TF_VAR_network_id")
 	if networkIdStr == "" {
@@ -10,29 +10,43 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
-	"log"
-	"net/http"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"strconv"
 	"strings"
 	"terraform-provider-vstack/internal/helper"
 	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/timeouts"
 	"terraform-provider-vstack/internal/vstack_api"
+	"time"
+)
+
+// Default timeouts of the VM resource operations. Creating a VM with many disks can take a while.
+const (
+	defaultVMCreateTimeout = 30 * time.Minute
+	defaultVMReadTimeout   = 5 * time.Minute
+	defaultVMUpdateTimeout = 30 * time.Minute
+	defaultVMDeleteTimeout = 15 * time.Minute
 )
 
 type VstackVMResource struct {
-	Client     *http.Client
-	BaseURL    string
-	AuthCookie string
+	Client        *vstack_api.Client
+	StatusTimeout time.Duration
+	LockConfig    helper.VMLockConfig
 }
 
+var _ resource.ResourceWithValidateConfig = &VstackVMResource{}
+var _ resource.ResourceWithModifyPlan = &VstackVMResource{}
+
 func NewVstackVMResource() resource.Resource {
 	return &VstackVMResource{}
 }
@@ -47,12 +61,12 @@ func (r *VstackVMResource) Configure(ctx context.Context, req resource.Configure
 	}
 	if providerData, ok := req.ProviderData.(*VStackProvider); ok {
 		r.Client = providerData.client
-		r.AuthCookie = providerData.authCookie
-		r.BaseURL = providerData.Host
-		if r.Client == nil || r.BaseURL == "" {
+		r.StatusTimeout = providerData.statusTimeout
+		r.LockConfig = providerData.lockConfig
+		if r.Client == nil {
 			resp.Diagnostics.AddError(
 				"Client Initialization Error",
-				"The HTTP client or baseURL was not properly initialized.",
+				"The vStack API client was not properly initialized.",
 			)
 		}
 	} else {
@@ -90,30 +104,27 @@ func (r *VstackVMResource) Schema(ctx context.Context, req resource.SchemaReques
 				Required:    true,
 			},
 			"cpu_priority": schema.Int64Attribute{
-				Description: "CPU priority of the virtual machine (1-20).",
+				Description: "CPU priority of the virtual machine (1-20). Changed in place on a running VM.",
 				Optional:    true,
 				Computed:    true,
 				PlanModifiers: []planmodifier.Int64{
 					int64planmodifier.UseStateForUnknown(),
-					int64planmodifier.RequiresReplace(),
 				},
 			},
 			"boot_media": schema.Int64Attribute{
-				Description: "ID of the boot media.",
+				Description: "ID of the boot media. Changing it on a running VM requires allow_reboot_for_update.",
 				Optional:    true,
 				Computed:    true,
 				PlanModifiers: []planmodifier.Int64{
 					int64planmodifier.UseStateForUnknown(),
-					int64planmodifier.RequiresReplace(),
 				},
 			},
 			"vcpu_class": schema.Int64Attribute{
-				Description: "Class of the vCPU for the virtual machine.",
+				Description: "Class of the vCPU for the virtual machine. Changing it on a running VM requires allow_reboot_for_update.",
 				Optional:    true,
 				Computed:    true,
 				PlanModifiers: []planmodifier.Int64{
 					int64planmodifier.UseStateForUnknown(),
-					int64planmodifier.RequiresReplace(),
 				},
 			},
 			"os_type": schema.Int64Attribute{
@@ -126,8 +137,9 @@ func (r *VstackVMResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 			"os_profile": schema.StringAttribute{
-				Description: "Operating system profile for the virtual machine.",
-				Required:    true,
+				Description: "Operating system profile for the virtual machine. Required unless the VM is cloned from a source, whose profile it inherits.",
+				Optional:    true,
+				Computed:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
@@ -141,12 +153,12 @@ func (r *VstackVMResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 			"node": schema.Int64Attribute{
-				Description: "Node on which the VM is running.",
-				Optional:    true,
-				Computed:    true,
+				Description: "Node on which the VM is running. Changing it migrates the VM to the node: live if it is running " +
+					"and migrate_on_update is set, offline otherwise.",
+				Optional: true,
+				Computed: true,
 				PlanModifiers: []planmodifier.Int64{
 					int64planmodifier.UseStateForUnknown(),
-					int64planmodifier.RequiresReplace(),
 				},
 			},
 			"uefi": schema.StringAttribute{
@@ -206,13 +218,50 @@ func (r *VstackVMResource) Schema(ctx context.Context, req resource.SchemaReques
 				Computed:    true,
 			},
 			"action": schema.StringAttribute{
-				Description: "Action to perform on the VM (e.g., 'start', 'stop').",
-				Optional:    true,
-				Computed:    true,
+				Description: "Action to perform on the VM: 'start', 'stop', 'shutdown', 'poweroff' or 'suspend'. " +
+					"'shutdown' asks the guest to shut down and powers the VM off after shutdown_timeout; 'stop' and 'poweroff' power it off right away. " +
+					"A suspended VM is resumed by 'start'.",
+				Optional: true,
+				Computed: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"shutdown_timeout": schema.StringAttribute{
+				Description: "Time the guest is given to shut down after an ACPI shutdown request, " +
+					"e.g. \"5m\", before the VM is powered off. Used by the 'shutdown' action and reboot_trigger. Defaults to 2m.",
+				Optional: true,
+				Validators: []validator.String{
+					timeouts.PositiveDuration(),
+				},
+			},
+			"reboot_trigger": schema.StringAttribute{
+				Description: "Arbitrary value; the running VM is rebooted, shut down gracefully and started again, whenever it changes. " +
+					"Setting it on a VM that had no reboot_trigger does not reboot the VM.",
+				Optional: true,
+			},
+			"allow_reboot_for_update": schema.BoolAttribute{
+				Description: "Allow the provider to stop and start a running VM to apply changes it cannot apply live, " +
+					"e.g. of boot_media and vcpu_class, of cpus and ram rejected by vStack, or of node without migrate_on_update. " +
+					"Defaults to false, which fails such changes instead.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"migrate_on_update": schema.BoolAttribute{
+				Description: "Migrate a running VM live to its new node when node changes. " +
+					"If false, the VM has to be stopped for the migration, which requires allow_reboot_for_update. Defaults to true.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"recreate_on_failure": schema.BoolAttribute{
+				Description: "Plan the replacement of the VM when it is found in a terminal failure status " +
+					"(CreateFailed, StartFailed, StopFailed or DeleteFailed). Defaults to false, which only reports the failure.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
 			"guest": schema.SingleNestedAttribute{
 				Description: "Guest customization for the VM.",
 				Required:    true,
@@ -320,8 +369,13 @@ func (r *VstackVMResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 			"disks": schema.ListNestedAttribute{
-				Description: "List of disks attached to the virtual machine.",
-				Required:    true,
+				Description: "List of disks attached to the virtual machine. Required unless the VM is cloned from a source: " +
+					"a clone gets the disks of the source, and the disks set here are then added, resized or removed by slot.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"guid": schema.StringAttribute{
@@ -389,13 +443,133 @@ func (r *VstackVMResource) Schema(ctx context.Context, req resource.SchemaReques
 					},
 				},
 			},
+			"nics": schema.ListNestedAttribute{
+				Description: "NICs of the virtual machine, e.g. those a clone got from its source. " +
+					"NICs are managed with vstack_nic resources; import them by <vm_id>/<port_id>.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"slot": schema.Int64Attribute{
+							Description: "Slot number of the NIC.",
+							Computed:    true,
+						},
+						"port_id": schema.Int64Attribute{
+							Description: "ID of the network port of the NIC.",
+							Computed:    true,
+						},
+						"network_id": schema.Int64Attribute{
+							Description: "ID of the network the NIC is attached to.",
+							Computed:    true,
+						},
+						"mac": schema.StringAttribute{
+							Description: "MAC address of the NIC.",
+							Computed:    true,
+						},
+						"address": schema.StringAttribute{
+							Description: "IP address of the NIC.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"source": schema.SingleNestedAttribute{
+				Description: "VM or snapshot to clone the virtual machine from instead of creating it from os_profile. " +
+					"The clone gets the disks and NICs of the source; the guest customization is applied on top.",
+				Optional: true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"vm_id": schema.Int64Attribute{
+						Description: "ID of the VM to clone. Conflicts with snapshot_id.",
+						Optional:    true,
+					},
+					"snapshot_id": schema.StringAttribute{
+						Description: "ID of the vstack_vm_snapshot to clone, <vm_id>/<root_dataset>@<name>. Conflicts with vm_id.",
+						Optional:    true,
+					},
+					"linked": schema.BoolAttribute{
+						Description: "Create a linked clone sharing the unchanged data with the source instead of a full copy. " +
+							"The source cannot be removed while linked clones exist.",
+						Optional: true,
+						Computed: true,
+						Default:  booldefault.StaticBool(false),
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
 
+// ValidateConfig checks that a VM is either created from os_profile and disks or cloned from exactly one source.
+func (r *VstackVMResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var source *models.VMSourceModel
+	var osProfile types.String
+	var osType types.Int64
+	var disks types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("source"), &source)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("os_profile"), &osProfile)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("os_type"), &osType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("disks"), &disks)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A VM created from scratch needs an OS profile and disks.
+	if source == nil {
+		if osProfile.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("os_profile"), "Missing os_profile", "os_profile must be set unless the VM is cloned from a source.")
+		}
+		if disks.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("disks"), "Missing disks", "disks must be set unless the VM is cloned from a source.")
+		}
+		return
+	}
+
+	// A clone inherits the OS of its source.
+	if !osProfile.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("os_profile"), "Conflicting os_profile", "os_profile cannot be set on a VM cloned from a source.")
+	}
+	if !osType.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("os_type"), "Conflicting os_type", "os_type cannot be set on a VM cloned from a source.")
+	}
+
+	// Values only known after apply cannot be validated yet.
+	if source.VmID.IsUnknown() || source.SnapshotID.IsUnknown() {
+		return
+	}
+
+	switch {
+	case !source.VmID.IsNull() && !source.SnapshotID.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("source").AtName("snapshot_id"), "Conflicting source", "Only one of vm_id or snapshot_id can be set.")
+	case source.VmID.IsNull() && source.SnapshotID.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("source"), "Missing source", "One of vm_id or snapshot_id must be set.")
+	case !source.SnapshotID.IsNull():
+		if _, _, _, err := helper.ParseSnapshotImportID(source.SnapshotID.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("source").AtName("snapshot_id"), "Invalid snapshot_id", err.Error())
+		}
+	}
+}
+
 // Create: creates a VM and manages its state (start/stop) as needed.
 func (r *VstackVMResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	// 1. Retrieve the plan from the request
+	ctx = withLogSubsystem(ctx, logSubsystemVM)
+
+	// 1. Retrieve the plan from the request. A clone without configured disks gets the disks
+	// of its source, which are only known after apply.
+	var planDisks types.List
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("disks"), &planDisks)...)
+	if planDisks.IsUnknown() {
+		resp.Diagnostics.Append(req.Plan.SetAttribute(ctx, path.Root("disks"), []models.DiskModel{})...)
+	}
 	var plan models.VMResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -403,21 +577,27 @@ func (r *VstackVMResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
+	// Bound the API calls and status polling of the operation by the create timeout
+	createTimeout, diags := timeouts.Create(ctx, plan.Timeouts, defaultVMCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// 2. Apply default sector size for disks
 	for i := range plan.Disks {
 		helper.ApplyDefaultSectorSize(&plan.Disks[i])
 	}
 
 	// 3. Prepare the guest payload for VM creation
-	guestPayload := make(map[string]interface{})
+	guestPayload := vstack_api.GuestParams{}
 
 	// Only access plan.Guest if it is non-nil
 
 	// Hostname
-	hostname := plan.Guest.Hostname.ValueString()
-	if hostname != "" {
-		guestPayload["hostname"] = hostname
-	}
+	guestPayload.Hostname = plan.Guest.Hostname.ValueString()
 
 	// BootCmds
 	if !plan.Guest.BootCmds.IsNull() && !plan.Guest.BootCmds.IsUnknown() {
@@ -427,7 +607,7 @@ func (r *VstackVMResource) Create(ctx context.Context, req resource.CreateReques
 			for _, cmd := range bootCmdsValues {
 				stringVal, ok := cmd.(basetypes.StringValue)
 				if !ok {
-					log.Printf("Warning: BootCmd is not basetypes.StringValue, skipping.")
+					tflog.SubsystemWarn(ctx, logSubsystemVM, "Skipping boot command that is not a string")
 					continue
 				}
 				val := stringVal.ValueString()
@@ -436,10 +616,10 @@ func (r *VstackVMResource) Create(ctx context.Context, req resource.CreateReques
 				}
 			}
 		} else {
-			log.Printf("Error retrieving BootCmds elements: %v", err)
+			tflog.SubsystemError(ctx, logSubsystemVM, "Error retrieving boot commands", map[string]any{"error": fmt.Sprint(err)})
 		}
 		if len(bootCmds) > 0 {
-			guestPayload["boot_cmds"] = bootCmds
+			guestPayload.BootCmds = bootCmds
 		}
 	}
 
@@ -451,7 +631,7 @@ func (r *VstackVMResource) Create(ctx context.Context, req resource.CreateReques
 			for _, cmd := range runCmdsValues {
 				stringVal, ok := cmd.(basetypes.StringValue)
 				if !ok {
-					log.Printf("Warning: RunCmd is not basetypes.StringValue, skipping.")
+					tflog.SubsystemWarn(ctx, logSubsystemVM, "Skipping run command that is not a string")
 					continue
 				}
 				val := stringVal.ValueString()
@@ -460,117 +640,102 @@ func (r *VstackVMResource) Create(ctx context.Context, req resource.CreateReques
 				}
 			}
 		} else {
-			log.Printf("Error retrieving RunCmds elements: %v", err)
+			tflog.SubsystemError(ctx, logSubsystemVM, "Error retrieving run commands", map[string]any{"error": fmt.Sprint(err)})
 		}
 		if len(runCmds) > 0 {
-			guestPayload["run_cmds"] = runCmds
+			guestPayload.RunCmds = runCmds
 		}
 	}
 
 	// SSH password authentication
-	sshPasswordAuth := plan.Guest.SSHPasswordAuth.ValueInt64()
-	if sshPasswordAuth != 0 {
-		guestPayload["ssh_password_auth"] = sshPasswordAuth
-	}
+	guestPayload.SSHPasswordAuth = plan.Guest.SSHPasswordAuth.ValueInt64()
 
 	// Resolver
 	if plan.Guest.Resolver != nil {
-		resolverPayload := make(map[string]interface{})
+		resolverPayload := vstack_api.ResolverParams{}
 
 		// Name servers
-		if len(plan.Guest.Resolver.NameServers) > 0 {
-			nsList := make([]string, 0, len(plan.Guest.Resolver.NameServers))
-			for _, ns := range plan.Guest.Resolver.NameServers {
-				val := ns.ValueString()
-				if val != "" {
-					nsList = append(nsList, val)
-				}
-			}
-			if len(nsList) > 0 {
-				resolverPayload["name_server"] = nsList
+		for _, ns := range plan.Guest.Resolver.NameServers {
+			val := ns.ValueString()
+			if val != "" {
+				resolverPayload.NameServer = append(resolverPayload.NameServer, val)
 			}
 		}
 
 		// Search domain
-		searchDomain := plan.Guest.Resolver.Search.ValueString()
-		if searchDomain != "" {
-			resolverPayload["search"] = searchDomain
-		}
+		resolverPayload.Search = plan.Guest.Resolver.Search.ValueString()
 
 		// Add resolver payload only if not empty
-		if len(resolverPayload) > 0 {
-			guestPayload["resolver"] = resolverPayload
+		if len(resolverPayload.NameServer) > 0 || resolverPayload.Search != "" {
+			guestPayload.Resolver = &resolverPayload
 		}
 	}
 
 	// Users
-
-	usersPayload := make(map[string]interface{})
 	for username, user := range plan.Guest.Users {
-		userPayload := make(map[string]interface{})
+		userPayload := vstack_api.UserParams{}
 
 		// SSH authorized keys
-		if len(user.SSHPublicKeys) > 0 {
-			sshKeys := make([]string, 0, len(user.SSHPublicKeys))
-			for _, key := range user.SSHPublicKeys {
-				keyVal := key.ValueString()
-				if keyVal != "" {
-					sshKeys = append(sshKeys, keyVal)
-				}
-			}
-			if len(sshKeys) > 0 {
-				userPayload["ssh-authorized-keys"] = sshKeys
+		for _, key := range user.SSHPublicKeys {
+			keyVal := key.ValueString()
+			if keyVal != "" {
+				userPayload.SSHAuthorizedKeys = append(userPayload.SSHAuthorizedKeys, keyVal)
 			}
 		}
 
 		// Password
-		pwVal := user.Password.ValueString()
-		if pwVal != "" {
-			userPayload["password"] = pwVal
-		}
+		userPayload.Password = user.Password.ValueString()
 
 		// Add user only if fields are non-empty
-		if len(userPayload) > 0 {
-			usersPayload[username] = userPayload
+		if len(userPayload.SSHAuthorizedKeys) > 0 || userPayload.Password != "" {
+			if guestPayload.Users == nil {
+				guestPayload.Users = make(map[string]vstack_api.UserParams)
+			}
+			guestPayload.Users[username] = userPayload
 		}
 	}
-	if len(usersPayload) > 0 {
-		guestPayload["users"] = usersPayload
-	}
 
 	// 4. Prepare the request for VM creation
-	params := map[string]interface{}{
-		"name":          plan.Name.ValueString(),
-		"cpus":          plan.CPUs.ValueInt64(),
-		"ram":           helper.ConvertMbToBytes(plan.RAM.ValueInt64()),
-		"boot_media":    plan.BootMedia.ValueInt64(),
-		"vcpu_class":    plan.VcpuClass.ValueInt64(),
-		"os_type":       plan.OsType.ValueInt64(),
-		"os_profile":    plan.OsProfile.ValueString(),
-		"vdc_id":        plan.VdcID.ValueInt64(),
-		"pool_selector": plan.PoolSelector.ValueString(),
-		"disks":         helper.FormatDisks(plan.Disks),
-		"description":   plan.Description.ValueString(),
+	params := vstack_api.VmCreateParams{
+		Name:         plan.Name.ValueString(),
+		CPUs:         plan.CPUs.ValueInt64(),
+		RAM:          helper.ConvertMbToBytes(plan.RAM.ValueInt64()),
+		BootMedia:    plan.BootMedia.ValueInt64(),
+		VcpuClass:    plan.VcpuClass.ValueInt64(),
+		OsType:       plan.OsType.ValueInt64(),
+		OsProfile:    plan.OsProfile.ValueString(),
+		VdcID:        plan.VdcID.ValueInt64(),
+		PoolSelector: plan.PoolSelector.ValueString(),
+		Disks:        helper.FormatDisks(plan.Disks),
+		Description:  plan.Description.ValueString(),
 	}
 
 	// Attach guest payload only if we have data
-	if len(guestPayload) > 0 {
-		params["guest"] = guestPayload
+	if !guestPayload.IsEmpty() {
+		params.Guest = &guestPayload
 	}
 
 	if plan.CPUPriority.IsNull() || plan.CPUPriority.IsUnknown() {
-		params["cpu_priority"] = 1
+		params.CpuPriority = 1
 	} else {
-		params["cpu_priority"] = plan.CPUPriority.ValueInt64()
+		params.CpuPriority = plan.CPUPriority.ValueInt64()
 	}
 
-	requestCreatePayload := helper.BuildJSONRPCRequest("vms-create", params)
-
-	// 5. Call the API to create the VM
-	apiCreateResponse, err := vstack_api.VmCreate(requestCreatePayload, r.AuthCookie, r.BaseURL, r.Client)
-	if err != nil {
-		resp.Diagnostics.AddError("Error on vstack_api.VmCreate func", err.Error())
-		return
+	// 5. Call the API to create the VM, or to clone it from the source
+	var apiCreateResponse vstack_api.VmCreateResult
+	var err error
+	if plan.Source != nil {
+		apiCreateResponse, err = r.CloneVM(ctx, plan, params)
+		if err != nil {
+			resp.Diagnostics.AddError("Error cloning VM", err.Error())
+			return
+		}
+	} else {
+		apiCreateResponse, err = r.Client.VmCreate(ctx, params)
+		if err != nil {
+			resp.Diagnostics.AddError("Error on vstack_api.VmCreate func", err.Error())
+			return
+		}
 	}
 
 	// 6. Retrieve the ID of the created VM
@@ -581,25 +746,37 @@ func (r *VstackVMResource) Create(ctx context.Context, req resource.CreateReques
 	}
 
 	// 7. Retrieve the mutex for the new VM and lock it
-	mu, lockErr := helper.GetVMLock(vmID)
+	unlock, lockErr := helper.GetVMLock(ctx, r.LockConfig, vmID, "vstack_vm create")
 	if lockErr != nil {
-		resp.Diagnostics.AddError("Error on GetVMLock", lockErr.Error())
+		resp.Diagnostics.AddError("Error acquiring VM lock", lockErr.Error())
 		return
 	}
-	mu.Lock()
-	defer mu.Unlock()
+	defer unlock()
+
+	// A VM that has entered a failure status is kept in the state, tainted, instead of being left behind
+	defer func() {
+		r.saveFailedVMOnError(ctx, plan, vmID, resp)
+	}()
+
+	// Disks configured for a clone are applied to the disks it got from the source before it is started
+	if plan.Source != nil && !planDisks.IsUnknown() {
+		resp.Diagnostics.Append(r.ReconcileClonedDisks(ctx, &plan, vmID)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// 8. Manage VM state (start/stop) based on plan.Action
 	action := strings.ToLower(plan.Action.ValueString())
 	switch action {
 	case "start":
-		if err := helper.PerformAction(r.Client, r.AuthCookie, r.BaseURL, vmID, "start"); err != nil {
-			resp.Diagnostics.AddError("Error starting VM", err.Error())
+		if err := helper.PerformAction(ctx, r.Client, vmID, "start", r.StatusTimeout); err != nil {
+			resp.Diagnostics.AddError("Error starting VM", vmFailureDetail(err))
 			return
 		}
-	case "stop":
+	case "stop", "shutdown", "poweroff":
 		// Check if we need to start before we can stop
-		isRunning, err := helper.CheckIfVMIsRunning(r.Client, r.AuthCookie, r.BaseURL, vmID)
+		isRunning, err := helper.CheckIfVMIsRunning(ctx, r.Client, vmID)
 		if err != nil {
 			resp.Diagnostics.AddError("Error checking VM status", err.Error())
 			return
@@ -607,37 +784,46 @@ func (r *VstackVMResource) Create(ctx context.Context, req resource.CreateReques
 
 		// If newly created or not running, start + stop
 		if apiCreateResponse.Data.OperStatus == helper.Status.Created || !isRunning {
-			if err := helper.PerformAction(r.Client, r.AuthCookie, r.BaseURL, vmID, "start"); err != nil {
-				resp.Diagnostics.AddError("Error starting VM before stopping", err.Error())
+			if err := helper.PerformAction(ctx, r.Client, vmID, "start", r.StatusTimeout); err != nil {
+				resp.Diagnostics.AddError("Error starting VM before stopping", vmFailureDetail(err))
 				return
 			}
 		}
 		// Stop
-		if err := helper.PerformAction(r.Client, r.AuthCookie, r.BaseURL, vmID, "stop"); err != nil {
-			resp.Diagnostics.AddError("Error stopping VM", err.Error())
+		if err := r.StopVM(ctx, vmID, action, plan.ShutdownTimeout); err != nil {
+			resp.Diagnostics.AddError("Error stopping VM", vmFailureDetail(err))
+			return
+		}
+	case "suspend":
+		// A new VM has to be started before it can be suspended
+		if err := helper.PerformAction(ctx, r.Client, vmID, "start", r.StatusTimeout); err != nil {
+			resp.Diagnostics.AddError("Error starting VM before suspending", vmFailureDetail(err))
+			return
+		}
+		if err := helper.PerformAction(ctx, r.Client, vmID, "suspend", r.StatusTimeout); err != nil {
+			resp.Diagnostics.AddError("Error suspending VM", vmFailureDetail(err))
 			return
 		}
 	case "":
-		if err := helper.PerformAction(r.Client, r.AuthCookie, r.BaseURL, vmID, "start"); err != nil {
-			resp.Diagnostics.AddError("Error starting VM", err.Error())
+		if err := helper.PerformAction(ctx, r.Client, vmID, "start", r.StatusTimeout); err != nil {
+			resp.Diagnostics.AddError("Error starting VM", vmFailureDetail(err))
 			return
 		}
 	default:
 		resp.Diagnostics.AddError("Invalid Action",
-			fmt.Sprintf("Unsupported action '%s'. Supported actions are 'start' or 'stop'.", action))
+			fmt.Sprintf("Unsupported action '%s'. Supported actions are 'start', 'stop', 'shutdown', 'poweroff' or 'suspend'.", action))
 		return
 	}
 
-	// 9. Retrieve the full VM details
-	requestReadVMPayload := helper.BuildJSONRPCRequest("vm-get", map[string]interface{}{
-		"id": vmID,
-	})
-
-	apiResponse, err := vstack_api.VmGet(requestReadVMPayload, r.AuthCookie, r.BaseURL, r.Client)
+	// 9. Retrieve the full VM details; a VM in a failure status is saved and reported, so Terraform taints it
+	apiResponse, err := r.Client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
 	if err != nil {
 		resp.Diagnostics.AddError("Error on vstack_api.VmGet func", err.Error())
 		return
 	}
+	if err := helper.CheckVMFailure(apiResponse); err != nil {
+		resp.Diagnostics.AddError("VM is in a failure status", vmFailureDetail(err))
+	}
 
 	// 10. Map the API response to Terraform state
 	updatedState, mapErr := helper.MapRespToState(apiResponse, plan)
@@ -655,11 +841,13 @@ func (r *VstackVMResource) Create(ctx context.Context, req resource.CreateReques
 	}
 
 	// 12. Log successful VM creation
-	log.Printf("Successfully created VM with ID %d", vmID)
+	tflog.SubsystemInfo(ctx, logSubsystemVM, "Created VM", map[string]any{"vm_id": vmID})
 }
 
 // Read: retrieves the current state of the VM from the API and updates the Terraform state.
 func (r *VstackVMResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemVM)
+
 	var state models.VMResourceModel
 
 	// Retrieve the current state for the VM ID
@@ -669,32 +857,42 @@ func (r *VstackVMResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
+	// Bound the API calls and status polling of the operation by the read timeout
+	readTimeout, diags := timeouts.Read(ctx, state.Timeouts, defaultVMReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	vmID := state.ID.ValueInt64()
 	if vmID == 0 {
 		resp.Diagnostics.AddError("Invalid VM ID", "VM ID is null or zero.")
 		return
 	}
 
-	// Lock the VM to prevent concurrent operations
-	mu, err := helper.GetVMLock(vmID)
+	// Lock the VM to prevent concurrent operations of this provider process. Reading does not change the VM,
+	// so it does not wait for other processes and does not write leases on every refresh.
+	unlock, err := helper.GetVMLock(ctx, helper.VMLockConfig{Timeout: r.LockConfig.Timeout}, vmID, "vstack_vm read")
 	if err != nil {
-		resp.Diagnostics.AddError("Error on GetVMLock", err.Error())
+		resp.Diagnostics.AddError("Error acquiring VM lock", err.Error())
 		return
 	}
-	mu.Lock()
-	defer mu.Unlock()
+	defer unlock()
 
 	// Call the vm-get API to get the latest VM information
-	requestPayload := helper.BuildJSONRPCRequest("vm-get", map[string]interface{}{
-		"id": vmID,
-	})
-
-	apiResponse, err := vstack_api.VmGet(requestPayload, r.AuthCookie, r.BaseURL, r.Client)
+	apiResponse, err := r.Client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
 	if err != nil {
 		resp.Diagnostics.AddError("Error on vstack_api.VmGet func", err.Error())
 		return
 	}
 
+	// Report a VM in a failure status without failing the refresh, so that it can still be replaced
+	if err := helper.CheckVMFailure(apiResponse); err != nil {
+		resp.Diagnostics.AddWarning("VM is in a failure status", vmFailureDetail(err))
+	}
+
 	// Map the API response to Terraform state
 	updatedState, mapErr := helper.MapRespToState(apiResponse, state)
 	if mapErr != nil {
@@ -702,6 +900,15 @@ func (r *VstackVMResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 	state = updatedState
+	if state.RecreateOnFailure.IsNull() {
+		state.RecreateOnFailure = types.BoolValue(false)
+	}
+	if state.AllowRebootForUpdate.IsNull() {
+		state.AllowRebootForUpdate = types.BoolValue(false)
+	}
+	if state.MigrateOnUpdate.IsNull() {
+		state.MigrateOnUpdate = types.BoolValue(true)
+	}
 
 	// Set the updated state
 	diags = resp.State.Set(ctx, state)
@@ -711,11 +918,13 @@ func (r *VstackVMResource) Read(ctx context.Context, req resource.ReadRequest, r
 	}
 
 	// Log successful VM state read
-	log.Printf("Successfully read VM state for VM ID %d", vmID)
+	tflog.SubsystemDebug(ctx, logSubsystemVM, "Read VM state", map[string]any{"vm_id": vmID})
 }
 
 // Update: updates the VM parameters and manages its state (start/stop).
 func (r *VstackVMResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemVM)
+
 	var plan, state models.VMResourceModel
 
 	// Retrieve the plan and current state
@@ -730,6 +939,15 @@ func (r *VstackVMResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	// Bound the API calls and status polling of the operation by the update timeout
+	updateTimeout, diags := timeouts.Update(ctx, plan.Timeouts, defaultVMUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	vmID := plan.ID.ValueInt64()
 	if vmID == 0 {
 		resp.Diagnostics.AddError("Invalid VM ID", "VM ID is null or zero.")
@@ -737,13 +955,12 @@ func (r *VstackVMResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 
 	// Lock the VM to prevent concurrent operations
-	mu, err := helper.GetVMLock(vmID)
+	unlock, err := helper.GetVMLock(ctx, r.LockConfig, vmID, "vstack_vm update")
 	if err != nil {
-		resp.Diagnostics.AddError("Error on GetVMLock", err.Error())
+		resp.Diagnostics.AddError("Error acquiring VM lock", err.Error())
 		return
 	}
-	mu.Lock()
-	defer mu.Unlock()
+	defer unlock()
 
 	// 1. Handle disk updates
 	diskDiags := r.UpdateDisks(ctx, &plan, &state)
@@ -753,106 +970,171 @@ func (r *VstackVMResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 
 	// 2. Collect changed VM parameters
-	vmParams := make(map[string]interface{})
+	vmParams := vstack_api.VmSetVmParams{}
 
 	if plan.Name.ValueString() != state.Name.ValueString() {
-		vmParams["name"] = plan.Name.ValueString()
+		vmParams.Name = plan.Name.ValueStringPointer()
 	}
 	if plan.Description.ValueString() != state.Description.ValueString() {
-		vmParams["description"] = plan.Description.ValueString()
+		description := plan.Description.ValueString()
+		vmParams.Description = &description
 	}
 	if plan.CPUs.ValueInt64() != state.CPUs.ValueInt64() {
-		vmParams["cpus"] = plan.CPUs.ValueInt64()
+		vmParams.CPUs = plan.CPUs.ValueInt64Pointer()
 	}
 	if plan.RAM.ValueInt64() != state.RAM.ValueInt64() {
-		vmParams["ram"] = helper.ConvertMbToBytes(plan.RAM.ValueInt64())
+		ram := helper.ConvertMbToBytes(plan.RAM.ValueInt64())
+		vmParams.RAM = &ram
 	}
 	if plan.CPUPriority.ValueInt64() != state.CPUPriority.ValueInt64() {
-		vmParams["cpu_priority"] = plan.CPUPriority.ValueInt64()
+		vmParams.CpuPriority = plan.CPUPriority.ValueInt64Pointer()
 	}
 	if plan.BootMedia.ValueInt64() != state.BootMedia.ValueInt64() {
-		vmParams["boot_media"] = plan.BootMedia.ValueInt64()
+		vmParams.BootMedia = plan.BootMedia.ValueInt64Pointer()
 	}
 	if plan.VcpuClass.ValueInt64() != state.VcpuClass.ValueInt64() {
-		vmParams["vcpu_class"] = plan.VcpuClass.ValueInt64()
+		vmParams.VcpuClass = plan.VcpuClass.ValueInt64Pointer()
 	}
 	if plan.OsType.ValueInt64() != state.OsType.ValueInt64() {
-		vmParams["os_type"] = plan.OsType.ValueInt64()
+		vmParams.OsType = plan.OsType.ValueInt64Pointer()
 	}
 	if plan.OsProfile.ValueString() != state.OsProfile.ValueString() {
-		vmParams["os_profile"] = plan.OsProfile.ValueString()
+		vmParams.OsProfile = plan.OsProfile.ValueStringPointer()
 	}
 	if plan.VdcID.ValueInt64() != state.VdcID.ValueInt64() {
-		vmParams["vdc_id"] = plan.VdcID.ValueInt64()
+		vmParams.VdcID = plan.VdcID.ValueInt64Pointer()
 	}
 	if plan.PoolSelector.ValueString() != state.PoolSelector.ValueString() {
-		vmParams["pool_selector"] = plan.PoolSelector.ValueString()
+		vmParams.PoolSelector = plan.PoolSelector.ValueStringPointer()
 	}
 
-	// 3. Update VM parameters if there are changes
-	if len(vmParams) > 0 {
-		requestPayload := helper.BuildJSONRPCRequest("vm-set", map[string]interface{}{
-			"id":        vmID,
-			"vm_params": vmParams,
-		})
-		if _, err := vstack_api.VmSet(requestPayload, r.AuthCookie, r.BaseURL, r.Client); err != nil {
-			resp.Diagnostics.AddError("Error updating VM parameters", err.Error())
+	// 3. Update VM parameters if there are changes, restarting the VM if they need it and it is allowed
+	if !vmParams.IsEmpty() {
+		resp.Diagnostics.Append(r.UpdateVMParams(ctx, plan, vmID, vmParams)...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
 	}
 
-	// 4. Manage VM state (start/stop) based on the plan
+	// 4. Migrate the VM to another node if its node changed
+	if !plan.Node.IsUnknown() && !plan.Node.IsNull() && plan.Node.ValueInt64() != state.Node.ValueInt64() {
+		resp.Diagnostics.Append(r.MigrateVM(ctx, plan, vmID, plan.Node.ValueInt64())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// 5. Manage VM state (start/stop) based on the plan
 	action := strings.ToLower(plan.Action.ValueString())
 	if action != "" {
+		// Check the current status of the VM
+		operStatus, err := helper.GetVMOperStatus(ctx, r.Client, vmID)
+		if err != nil {
+			resp.Diagnostics.AddError("Error checking VM status", err.Error())
+			return
+		}
+
 		switch action {
 		case "start":
+			// A suspended VM is resumed rather than started
+			if operStatus == helper.Status.Suspended {
+				if err := helper.PerformAction(ctx, r.Client, vmID, "resume", r.StatusTimeout); err != nil {
+					resp.Diagnostics.AddError("Error resuming VM", vmFailureDetail(err))
+					return
+				}
+				break
+			}
+
+			// A running VM is left alone: "vms-restart" would reboot it, e.g. after a live change or migration
+			if operStatus == helper.Status.Started {
+				break
+			}
+
 			// Execute the "start" action
-			if err := helper.PerformAction(r.Client, r.AuthCookie, r.BaseURL, vmID, "start"); err != nil {
-				resp.Diagnostics.AddError("Error starting VM", err.Error())
+			if err := helper.PerformAction(ctx, r.Client, vmID, "start", r.StatusTimeout); err != nil {
+				resp.Diagnostics.AddError("Error starting VM", vmFailureDetail(err))
 				return
 			}
-		case "stop":
-			// Check the current status of the VM
-			isRunning, err := helper.CheckIfVMIsRunning(r.Client, r.AuthCookie, r.BaseURL, vmID)
-			if err != nil {
-				resp.Diagnostics.AddError("Error checking VM status", err.Error())
-				return
+		case "stop", "shutdown", "poweroff":
+			// Only "stop" starts a VM that is not running to stop it again
+			if action != "stop" && operStatus != helper.Status.Started && operStatus != helper.Status.Suspended {
+				break
 			}
 
-			if state.OperStatus.ValueInt64() == helper.Status.Created || !isRunning {
+			switch operStatus {
+			case helper.Status.Started:
+				// The VM is running and can be stopped right away
+			case helper.Status.Suspended:
+				// A suspended VM is resumed and then stopped
+				if err := helper.PerformAction(ctx, r.Client, vmID, "resume", r.StatusTimeout); err != nil {
+					resp.Diagnostics.AddError("Error resuming VM before stopping", vmFailureDetail(err))
+					return
+				}
+			default:
 				// If status is "Created" or VM is not running, start and then stop
-				if err := helper.PerformAction(r.Client, r.AuthCookie, r.BaseURL, vmID, "start"); err != nil {
-					resp.Diagnostics.AddError("Error starting VM before stopping", err.Error())
+				if err := helper.PerformAction(ctx, r.Client, vmID, "start", r.StatusTimeout); err != nil {
+					resp.Diagnostics.AddError("Error starting VM before stopping", vmFailureDetail(err))
 					return
 				}
 			}
 
 			// Stop the VM
-			if err := helper.PerformAction(r.Client, r.AuthCookie, r.BaseURL, vmID, "stop"); err != nil {
-				resp.Diagnostics.AddError("Error stopping VM", err.Error())
+			if err := r.StopVM(ctx, vmID, action, plan.ShutdownTimeout); err != nil {
+				resp.Diagnostics.AddError("Error stopping VM", vmFailureDetail(err))
+				return
+			}
+		case "suspend":
+			if operStatus == helper.Status.Suspended {
+				break
+			}
+
+			// Only a running VM can be suspended
+			if operStatus != helper.Status.Started {
+				if err := helper.PerformAction(ctx, r.Client, vmID, "start", r.StatusTimeout); err != nil {
+					resp.Diagnostics.AddError("Error starting VM before suspending", vmFailureDetail(err))
+					return
+				}
+			}
+
+			// Suspend the VM
+			if err := helper.PerformAction(ctx, r.Client, vmID, "suspend", r.StatusTimeout); err != nil {
+				resp.Diagnostics.AddError("Error suspending VM", vmFailureDetail(err))
 				return
 			}
 		default:
-			resp.Diagnostics.AddError("Invalid Action", fmt.Sprintf("Unsupported action '%s'. Supported actions are 'start' and 'stop'.", action))
+			resp.Diagnostics.AddError("Invalid Action", fmt.Sprintf("Unsupported action '%s'. Supported actions are 'start', 'stop', 'shutdown', 'poweroff' and 'suspend'.", action))
 			return
 		}
 
-		// Reset the action in the plan to avoid saving it
-		plan.Action = types.StringValue("")
 	}
 
-	// 5. Retrieve the full information of the VM to set the state
-	requestReadVMPayload := helper.BuildJSONRPCRequest("vm-get", map[string]interface{}{
-		"id": vmID,
-	})
+	// 6. Reboot the VM if its reboot trigger changed. Setting the trigger for the first time does not reboot the VM.
+	if !state.RebootTrigger.IsNull() && !plan.RebootTrigger.Equal(state.RebootTrigger) {
+		rebootDiags := r.RebootVM(ctx, vmID, plan.ShutdownTimeout)
+		resp.Diagnostics.Append(rebootDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
-	apiResponse, err := vstack_api.VmGet(requestReadVMPayload, r.AuthCookie, r.BaseURL, r.Client)
+	// 7. Retrieve the full information of the VM to set the state; a VM in a failure status is reported
+	apiResponse, err := r.Client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
 	if err != nil {
 		resp.Diagnostics.AddError("Error on vstack_api.VmGet func", err.Error())
 		return
 	}
+	if err := helper.CheckVMFailure(apiResponse); err != nil {
+		resp.Diagnostics.AddError("VM is in a failure status", vmFailureDetail(err))
+	}
 
-	// 6. Map the API response to Terraform state
+	// 8. Map the API response to Terraform state, keeping the configured action, power, update and failure settings and timeouts
+	state.Action = plan.Action
+	state.ShutdownTimeout = plan.ShutdownTimeout
+	state.RebootTrigger = plan.RebootTrigger
+	state.RecreateOnFailure = plan.RecreateOnFailure
+	state.AllowRebootForUpdate = plan.AllowRebootForUpdate
+	state.MigrateOnUpdate = plan.MigrateOnUpdate
+	state.Timeouts = plan.Timeouts
 	updatedState, mapErr := helper.MapRespToState(apiResponse, state)
 	if mapErr != nil {
 		resp.Diagnostics.AddError("Error mapping response to state in Update", mapErr.Error())
@@ -860,7 +1142,7 @@ func (r *VstackVMResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 	state = updatedState
 
-	// 7. Set the updated state
+	// 9. Set the updated state
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -868,11 +1150,13 @@ func (r *VstackVMResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 
 	// Log successful VM update
-	log.Printf("Successfully updated VM with ID %d", vmID)
+	tflog.SubsystemInfo(ctx, logSubsystemVM, "Updated VM", map[string]any{"vm_id": vmID})
 }
 
 // Delete: deletes the VM.
 func (r *VstackVMResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemVM)
+
 	var state models.VMResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -880,6 +1164,15 @@ func (r *VstackVMResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
+	// Bound the API calls and status polling of the operation by the delete timeout
+	deleteTimeout, diags := timeouts.Delete(ctx, state.Timeouts, defaultVMDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	vmID := state.ID.ValueInt64()
 	if vmID == 0 {
 		resp.Diagnostics.AddError("Invalid VM ID", "VM ID is null or zero.")
@@ -887,35 +1180,42 @@ func (r *VstackVMResource) Delete(ctx context.Context, req resource.DeleteReques
 	}
 
 	// Lock the VM to prevent concurrent operations
-	mu, err := helper.GetVMLock(vmID)
+	unlock, err := helper.GetVMLock(ctx, r.LockConfig, vmID, "vstack_vm delete")
 	if err != nil {
-		resp.Diagnostics.AddError("Error on GetVMLock", err.Error())
+		resp.Diagnostics.AddError("Error acquiring VM lock", err.Error())
 		return
 	}
-	mu.Lock()
-	defer mu.Unlock()
+	defer unlock()
 
 	// 1. Check if the VM was running
-	wasRunning, err := helper.CheckIfVMIsRunning(r.Client, r.AuthCookie, r.BaseURL, vmID)
+	operStatus, err := helper.GetVMOperStatus(ctx, r.Client, vmID)
 	if err != nil {
 		resp.Diagnostics.AddError("Error checking VM status", err.Error())
 		return
 	}
 
+	// A suspended VM is resumed so that it can be stopped
+	if operStatus == helper.Status.Suspended {
+		if err := helper.PerformAction(ctx, r.Client, vmID, "resume", r.StatusTimeout); err != nil {
+			resp.Diagnostics.AddError("Error resuming VM", vmFailureDetail(err))
+			return
+		}
+		operStatus = helper.Status.Started
+	}
+
 	// 2. Stop the VM if it was running
-	if wasRunning {
-		if err := helper.PerformAction(r.Client, r.AuthCookie, r.BaseURL, vmID, "stop"); err != nil {
-			resp.Diagnostics.AddError("Error stopping VM", err.Error())
+	if operStatus == helper.Status.Started {
+		if err := helper.PerformAction(ctx, r.Client, vmID, "stop", r.StatusTimeout); err != nil {
+			resp.Diagnostics.AddError("Error stopping VM", vmFailureDetail(err))
 			return
 		}
 	}
 
 	// 3. Call the API to delete the VM
-	requestPayload := helper.BuildJSONRPCRequest("vms-remove", map[string]interface{}{
-		"id":     vmID,
-		"vdc_id": state.VdcID.ValueInt64(),
+	_, err = r.Client.VmRemove(ctx, vstack_api.VmRemoveParams{
+		ID:    vmID,
+		VdcID: state.VdcID.ValueInt64(),
 	})
-	_, err = vstack_api.VmRemove(requestPayload, r.AuthCookie, r.BaseURL, r.Client)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting VM", err.Error())
 		return
@@ -925,11 +1225,13 @@ func (r *VstackVMResource) Delete(ctx context.Context, req resource.DeleteReques
 	resp.State.RemoveResource(ctx)
 
 	// Log successful VM deletion
-	log.Printf("Successfully deleted VM with ID %d", vmID)
+	tflog.SubsystemInfo(ctx, logSubsystemVM, "Deleted VM", map[string]any{"vm_id": vmID})
 }
 
 // ImportState handles the import logic for the VM resource.
 func (r *VstackVMResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemVM)
+
 	// The ID provided in the terraform import command
 	importID := req.ID
 
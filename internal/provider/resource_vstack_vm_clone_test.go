@@ -0,0 +1,160 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccVStackVMClone tests cloning the vstack_vm resource from a VM and from a snapshot.
+func TestAccVStackVMClone(t *testing.T) {
+	resourceConfigTemplate := `
+resource "vstack_vm" "golden" {
+  name       = "tf-acc-golden"
+  cpus       = 1
+  ram        = 2048
+  os_profile = var.os_profile
+  vdc_id     = var.vdc_id
+  action     = "stop"
+
+  disks = [
+    {
+      size  = 20
+      slot  = 1
+      label = "system"
+    }
+  ]
+
+  guest = {
+    hostname = "tf-acc-golden"
+    users = {
+      root = {
+        ssh_authorized_keys = []
+        password            = "rootpassword"
+      }
+    }
+  }
+}
+
+resource "vstack_nic" "golden" {
+  vm_id      = vstack_vm.golden.id
+  network_id = var.network_id
+  slot       = 1
+}
+
+resource "vstack_vm_snapshot" "golden" {
+  vm_id        = vstack_vm.golden.id
+  root_dataset = vstack_vm.golden.root_dataset
+  name         = "golden"
+
+  depends_on = [vstack_nic.golden]
+}
+
+# A full clone of the VM with an additional disk.
+resource "vstack_vm" "from_vm" {
+  name   = "tf-acc-clone-vm"
+  cpus   = 2
+  ram    = 2048
+  vdc_id = var.vdc_id
+
+  source = {
+    vm_id = vstack_vm.golden.id
+  }
+
+  disks = [
+    {
+      size  = 20
+      slot  = 1
+      label = "system"
+    },
+    {
+      size = 10
+      slot = 2
+    }
+  ]
+
+  guest = {
+    hostname = "tf-acc-clone-vm"
+    users = {
+      root = {
+        ssh_authorized_keys = []
+        password            = "rootpassword"
+      }
+    }
+  }
+
+  depends_on = [vstack_nic.golden]
+}
+
+# A linked clone of the snapshot keeping the disks of the source.
+resource "vstack_vm" "from_snapshot" {
+  name   = "tf-acc-clone-snapshot"
+  cpus   = 1
+  ram    = 2048
+  vdc_id = var.vdc_id
+
+  source = {
+    snapshot_id = vstack_vm_snapshot.golden.id
+    linked      = true
+  }
+
+  guest = {
+    hostname = "tf-acc-clone-snapshot"
+    users = {
+      root = {
+        ssh_authorized_keys = []
+        password            = "rootpassword"
+      }
+    }
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// A VM needs either os_profile and disks or a source.
+				Config: providerConfigTemplate + `
+resource "vstack_vm" "invalid" {
+  name   = "tf-acc-invalid"
+  cpus   = 1
+  ram    = 2048
+  vdc_id = var.vdc_id
+
+  guest = {
+    hostname = "tf-acc-invalid"
+    users    = {}
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`os_profile must be set unless the VM is cloned from a source`),
+			},
+			{
+				Config: providerConfigTemplate + resourceConfigTemplate,
+				Check: resource.ComposeTestCheckFunc(
+					// The clone of the VM inherits the OS and gets the NIC and the disks of the source.
+					resource.TestCheckResourceAttrPair("vstack_vm.from_vm", "os_profile", "vstack_vm.golden", "os_profile"),
+					resource.TestCheckResourceAttr("vstack_vm.from_vm", "cpus", "2"),
+					resource.TestCheckResourceAttr("vstack_vm.from_vm", "source.linked", "false"),
+					resource.TestCheckResourceAttr("vstack_vm.from_vm", "disks.#", "2"),
+					resource.TestCheckResourceAttr("vstack_vm.from_vm", "disks.0.label", "system"),
+					resource.TestCheckResourceAttr("vstack_vm.from_vm", "disks.1.size", "10"),
+					resource.TestCheckResourceAttr("vstack_vm.from_vm", "nics.#", "1"),
+					resource.TestCheckResourceAttr("vstack_vm.from_vm", "nics.0.slot", "1"),
+					resource.TestCheckResourceAttr("vstack_vm.from_vm", "action", "start"),
+
+					// The clone of the snapshot reports the disks it got from the source.
+					resource.TestCheckResourceAttr("vstack_vm.from_snapshot", "disks.#", "1"),
+					resource.TestCheckResourceAttr("vstack_vm.from_snapshot", "disks.0.size", "20"),
+					resource.TestCheckResourceAttr("vstack_vm.from_snapshot", "nics.#", "1"),
+					resource.TestCheckResourceAttrPair("vstack_vm.from_snapshot", "nics.0.network_id", "vstack_nic.golden", "network_id"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,406 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/timeouts"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// Default timeouts of the VM snapshot resource operations.
+const (
+	defaultSnapshotCreateTimeout = 10 * time.Minute
+	defaultSnapshotReadTimeout   = 5 * time.Minute
+	defaultSnapshotDeleteTimeout = 10 * time.Minute
+)
+
+// VstackVMSnapshotResource is the resource responsible for managing a snapshot of the root dataset of a VM.
+type VstackVMSnapshotResource struct {
+	Client     *vstack_api.Client
+	LockConfig helper.VMLockConfig
+}
+
+// Ensure VstackVMSnapshotResource satisfies the Terraform interfaces.
+var (
+	_ resource.Resource                = &VstackVMSnapshotResource{}
+	_ resource.ResourceWithConfigure   = &VstackVMSnapshotResource{}
+	_ resource.ResourceWithImportState = &VstackVMSnapshotResource{}
+)
+
+// NewVstackVMSnapshotResource initializes the VM snapshot resource.
+func NewVstackVMSnapshotResource() resource.Resource {
+	return &VstackVMSnapshotResource{}
+}
+
+// Metadata sets the name of the resource.
+func (r *VstackVMSnapshotResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_snapshot"
+}
+
+// Schema defines the schema for the VM snapshot resource. A snapshot cannot be changed once it is taken,
+// so changing any attribute but retention replaces it.
+func (r *VstackVMSnapshotResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a snapshot of the root dataset of a vStack VM. The snapshot is taken when the resource is " +
+			"created and removed when it is destroyed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the snapshot: <vm_id>/<root_dataset>@<name>. It is also the import ID.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"vm_id": schema.Int64Attribute{
+				Description: "ID of the VM to snapshot. Changing it replaces the snapshot.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"root_dataset": schema.StringAttribute{
+				Description: "Dataset to snapshot, the root_dataset of the vstack_vm. Defaults to the root dataset of the VM. " +
+					"Changing it replaces the snapshot.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the snapshot, unique within the dataset. If not provided, vStack generates one. " +
+					"Changing it replaces the snapshot.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringExcludes("@/"),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Description of the snapshot. Changing it replaces the snapshot.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"retention": schema.StringAttribute{
+				Description: "How long vStack keeps the snapshot, as a Go duration string (e.g. 168h). If not provided, " +
+					"the snapshot is kept until the resource is destroyed. Once it expires, Terraform plans to take it again. " +
+					"Changing it replaces the snapshot, except when it is set for an imported snapshot that expires.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(retentionRequiresReplace,
+						"Changing the retention of a snapshot replaces it.",
+						"Changing the retention of a snapshot replaces it."),
+				},
+				Validators: []validator.String{
+					timeouts.PositiveDuration(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Description: "Time the snapshot was taken at, in RFC 3339 format.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"expires_at": schema.StringAttribute{
+				Description: "Time vStack removes the snapshot at, in RFC 3339 format. Empty if the snapshot has no retention.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// retentionRequiresReplace replaces the snapshot when its retention changes. vStack does not return the
+// retention, so a snapshot imported with an expiry time adopts the configured retention instead.
+func retentionRequiresReplace(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	if !req.StateValue.IsNull() {
+		resp.RequiresReplace = true
+		return
+	}
+
+	var expiresAt types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("expires_at"), &expiresAt)...)
+	resp.RequiresReplace = expiresAt.ValueString() == ""
+}
+
+// Configure sets up the resource with provider data.
+func (r *VstackVMSnapshotResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	if pd, ok := req.ProviderData.(*VStackProvider); ok {
+		r.Client = pd.client
+		r.LockConfig = pd.lockConfig
+	} else {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data Type",
+			"The provider data was not of the expected *VStackProvider type.",
+		)
+	}
+}
+
+// Create takes the snapshot.
+func (r *VstackVMSnapshotResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemSnapshot)
+
+	var plan models.VMSnapshotModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Bound the API calls of the operation by the create timeout
+	createTimeout, diags := timeouts.Create(ctx, plan.Timeouts, defaultSnapshotCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	vmID := plan.VmID.ValueInt64()
+	if vmID == 0 {
+		resp.Diagnostics.AddError("Invalid VM ID", "VM ID must be greater than zero.")
+		return
+	}
+
+	// 1. Build the request, defaulting to the root dataset of the VM
+	params := vstack_api.VmSnapshotsCreateParams{
+		ID:          vmID,
+		Dataset:     plan.RootDataset.ValueString(),
+		Name:        plan.Name.ValueString(),
+		Description: plan.Description.ValueString(),
+	}
+	if params.Dataset == "" {
+		vmResp, err := r.Client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
+		if err != nil {
+			resp.Diagnostics.AddError("Error retrieving VM", err.Error())
+			return
+		}
+		params.Dataset = fmt.Sprintf("%v", vmResp.Data.RootDataset)
+	}
+	if !plan.Retention.IsNull() {
+		retention, err := time.ParseDuration(plan.Retention.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("retention"), "Invalid retention", err.Error())
+			return
+		}
+		params.Retention = int64(retention.Seconds())
+	}
+
+	// 2. Lock the VM to prevent concurrent operations
+	unlock, err := helper.GetVMLock(ctx, r.LockConfig, vmID, "vstack_vm_snapshot create")
+	if err != nil {
+		resp.Diagnostics.AddError("Error acquiring VM lock", err.Error())
+		return
+	}
+	defer unlock()
+
+	// 3. Take the snapshot
+	createResp, err := r.Client.VmSnapshotsCreate(ctx, params)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating VM snapshot", err.Error())
+		return
+	}
+
+	// 4. Map the snapshot to the state
+	mapSnapshotToModel(createResp.Data, &plan)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Log successful snapshot creation
+	tflog.SubsystemInfo(ctx, logSubsystemSnapshot, "Created VM snapshot", map[string]any{"vm_id": vmID, "snapshot_id": plan.ID.ValueString()})
+}
+
+// Read retrieves the snapshot from vStack and updates the Terraform state.
+func (r *VstackVMSnapshotResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemSnapshot)
+
+	var state models.VMSnapshotModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Bound the API calls of the operation by the read timeout
+	readTimeout, diags := timeouts.Read(ctx, state.Timeouts, defaultSnapshotReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	vmID := state.VmID.ValueInt64()
+	snapshotID := state.ID.ValueString()
+
+	// Find the snapshot. A snapshot that expired or was removed outside Terraform is removed from the state;
+	// errors, e.g. a held lock or a lost connection, fail the read instead of dropping a snapshot that may still exist.
+	snapshot, found, err := helper.FindVMSnapshot(ctx, r.Client, vmID, state.RootDataset.ValueString(), state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error retrieving VM snapshot", err.Error())
+		return
+	}
+	if !found {
+		tflog.SubsystemWarn(ctx, logSubsystemSnapshot, "VM snapshot not found, removing it from state", map[string]any{"vm_id": vmID, "snapshot_id": snapshotID})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	mapSnapshotToModel(snapshot, &state)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Log successful snapshot state read
+	tflog.SubsystemDebug(ctx, logSubsystemSnapshot, "Read VM snapshot state", map[string]any{"vm_id": vmID, "snapshot_id": snapshotID})
+}
+
+// Update stores the retention set for an imported snapshot or changed timeouts. Every other change
+// replaces the snapshot, so nothing is sent to vStack.
+func (r *VstackVMSnapshotResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state models.VMSnapshotModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Retention = plan.Retention
+	state.Timeouts = plan.Timeouts
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes the snapshot. A snapshot that no longer exists is considered removed.
+func (r *VstackVMSnapshotResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withLogSubsystem(ctx, logSubsystemSnapshot)
+
+	var state models.VMSnapshotModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Bound the API calls of the operation by the delete timeout
+	deleteTimeout, diags := timeouts.Delete(ctx, state.Timeouts, defaultSnapshotDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	vmID := state.VmID.ValueInt64()
+	dataset := state.RootDataset.ValueString()
+	name := state.Name.ValueString()
+
+	// 1. Lock the VM to prevent concurrent operations
+	unlock, err := helper.GetVMLock(ctx, r.LockConfig, vmID, "vstack_vm_snapshot delete")
+	if err != nil {
+		resp.Diagnostics.AddError("Error acquiring VM lock", err.Error())
+		return
+	}
+	defer unlock()
+
+	// 2. Remove the snapshot. If vStack rejects the removal, check whether the snapshot is already gone.
+	_, err = r.Client.VmSnapshotsRemove(ctx, vstack_api.VmSnapshotsRemoveParams{ID: vmID, Dataset: dataset, Name: name})
+	var apiErr *vstack_api.Error
+	if err != nil && errors.As(err, &apiErr) {
+		_, found, findErr := helper.FindVMSnapshot(ctx, r.Client, vmID, dataset, name)
+		if findErr == nil && !found {
+			tflog.SubsystemWarn(ctx, logSubsystemSnapshot, "VM snapshot already removed", map[string]any{"vm_id": vmID, "snapshot_id": state.ID.ValueString()})
+			err = nil
+		}
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting VM snapshot", err.Error())
+		return
+	}
+
+	// 3. Remove the resource from Terraform state
+	resp.State.RemoveResource(ctx)
+
+	// Log successful snapshot deletion
+	tflog.SubsystemInfo(ctx, logSubsystemSnapshot, "Deleted VM snapshot", map[string]any{"vm_id": vmID, "snapshot_id": state.ID.ValueString()})
+}
+
+// ImportState imports a snapshot by its ID, "<vm_id>/<root_dataset>@<name>".
+func (r *VstackVMSnapshotResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	vmID, dataset, name, err := helper.ParseSnapshotImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vm_id"), vmID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("root_dataset"), dataset)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
+
+// mapSnapshotToModel copies the attributes of the snapshot returned by vStack into the resource model.
+// The retention is kept as configured, since vStack only returns the resulting expiry time.
+func mapSnapshotToModel(snapshot vstack_api.VmSnapshot, model *models.VMSnapshotModel) {
+	model.ID = types.StringValue(helper.SnapshotID(snapshot.VmID, snapshot.Dataset, snapshot.Name))
+	model.VmID = types.Int64Value(snapshot.VmID)
+	model.RootDataset = types.StringValue(snapshot.Dataset)
+	model.Name = types.StringValue(snapshot.Name)
+	model.Description = types.StringValue(snapshot.Description)
+	model.CreatedAt = types.StringValue(helper.FormatSnapshotTime(snapshot.Created))
+	model.ExpiresAt = types.StringValue(helper.FormatSnapshotTime(snapshot.Expires))
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccVStackVMSnapshot tests the vstack_vm_snapshot resource and lists the snapshot with the data source.
+func TestAccVStackVMSnapshot(t *testing.T) {
+	resourceConfigTemplate := `
+resource "vstack_vm" "test" {
+  name       = "tf-acc-snapshot"
+  cpus       = 1
+  ram        = 2048
+  os_profile = var.os_profile
+  vdc_id     = var.vdc_id
+
+  disks = [
+    {
+      size = 20
+      slot = 1
+    }
+  ]
+
+  guest = {
+    hostname = "tf-acc-snapshot"
+    users = {
+      root = {
+        ssh_authorized_keys = []
+        password            = "rootpassword"
+      }
+    }
+  }
+}
+
+resource "vstack_vm_snapshot" "test" {
+  vm_id        = vstack_vm.test.id
+  root_dataset = vstack_vm.test.root_dataset
+  name         = "before-upgrade"
+  description  = "Taken by the acceptance test"
+  retention    = "24h"
+}
+
+data "vstack_vm_snapshots" "test" {
+  vm_id = vstack_vm.test.id
+
+  depends_on = [vstack_vm_snapshot.test]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfigTemplate + resourceConfigTemplate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("vstack_vm_snapshot.test", "root_dataset", "vstack_vm.test", "root_dataset"),
+					resource.TestCheckResourceAttr("vstack_vm_snapshot.test", "name", "before-upgrade"),
+					resource.TestCheckResourceAttrSet("vstack_vm_snapshot.test", "created_at"),
+					resource.TestCheckResourceAttrSet("vstack_vm_snapshot.test", "expires_at"),
+
+					// The data source lists the snapshot with the same ID.
+					resource.TestCheckResourceAttr("data.vstack_vm_snapshots.test", "snapshots.#", "1"),
+					resource.TestCheckResourceAttrPair("data.vstack_vm_snapshots.test", "snapshots.0.id", "vstack_vm_snapshot.test", "id"),
+					resource.TestCheckResourceAttr("data.vstack_vm_snapshots.test", "snapshots.0.description", "Taken by the acceptance test"),
+				),
+			},
+			{
+				ResourceName:      "vstack_vm_snapshot.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"retention",
+					"timeouts",
+				},
+			},
+		},
+	})
+}
@@ -4,7 +4,12 @@
 package provider
 
 import (
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"regexp"
+	"strconv"
+	"terraform-provider-vstack/internal/helper"
 	"testing"
 )
 
@@ -299,3 +304,354 @@ resource "vstack_vm" "test_vm2" {
 		},
 	})
 }
+
+// TestAccVStackVM_suspend tests suspending a VM with action = "suspend" and resuming it with "start".
+func TestAccVStackVM_suspend(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVMPowerConfig(`action = "start"`),
+				Check:  resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+			},
+			{
+				Config: testAccVMPowerConfig(`action = "suspend"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_vm.power", "action", "suspend"),
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "11"),
+				),
+			},
+			{
+				// "start" resumes a suspended VM.
+				Config: testAccVMPowerConfig(`action = "start"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_vm.power", "action", "start"),
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccVStackVM_shutdown tests stopping a VM with action = "shutdown" and "poweroff".
+func TestAccVStackVM_shutdown(t *testing.T) {
+	t.Cleanup(func() {
+		if mockServer != nil {
+			mockServer.IgnoreShutdown = false
+		}
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVMPowerConfig(`action = "start"`),
+				Check:  resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+			},
+			{
+				Config: testAccVMPowerConfig(`action = "shutdown"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_vm.power", "action", "shutdown"),
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "1"),
+				),
+			},
+			{
+				Config: testAccVMPowerConfig(`action = "start"`),
+				Check:  resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+			},
+			{
+				// A guest ignoring the shutdown request is powered off after shutdown_timeout.
+				SkipFunc:  skipWithoutMock,
+				PreConfig: func() { mockServer.IgnoreShutdown = true },
+				Config: testAccVMPowerConfig(`action = "shutdown"
+  shutdown_timeout = "1s"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_vm.power", "action", "shutdown"),
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "1"),
+				),
+			},
+			{
+				SkipFunc:  skipWithoutMock,
+				PreConfig: func() { mockServer.IgnoreShutdown = false },
+				Config:    testAccVMPowerConfig(`action = "start"`),
+				Check:     resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+			},
+			{
+				Config: testAccVMPowerConfig(`action = "poweroff"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_vm.power", "action", "poweroff"),
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "1"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccVStackVM_rebootTrigger tests rebooting a running VM by changing reboot_trigger.
+func TestAccVStackVM_rebootTrigger(t *testing.T) {
+	// Calls of the fake vStack API made before a step, see testAccCheckMockCalls.
+	var shutdowns int
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVMPowerConfig(`action = "start"`),
+				Check:  resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+			},
+			{
+				// Setting reboot_trigger for the first time does not reboot the VM.
+				SkipFunc:  skipWithoutMock,
+				PreConfig: func() { shutdowns = mockServer.Calls("vms-shutdown") },
+				Config: testAccVMPowerConfig(`action = "start"
+  reboot_trigger = "1"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_vm.power", "reboot_trigger", "1"),
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+					testAccCheckMockCalls("vms-shutdown", &shutdowns, 0),
+				),
+			},
+			{
+				// Changing reboot_trigger shuts the VM down and starts it again.
+				SkipFunc:  skipWithoutMock,
+				PreConfig: func() { shutdowns = mockServer.Calls("vms-shutdown") },
+				Config: testAccVMPowerConfig(`action = "start"
+  reboot_trigger = "2"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_vm.power", "reboot_trigger", "2"),
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+					testAccCheckMockCalls("vms-shutdown", &shutdowns, 1),
+				),
+			},
+		},
+	})
+}
+
+// TestAccVStackVM_recreateOnFailure tests replacing VMs in a failure status. It needs the fake vStack API
+// to fail the VMs, so its steps are skipped against a real cluster.
+func TestAccVStackVM_recreateOnFailure(t *testing.T) {
+	t.Cleanup(func() {
+		if mockServer != nil {
+			mockServer.FailStarts = false
+		}
+	})
+
+	// ID of the VM failed by the fake vStack API.
+	var failedID string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				SkipFunc: skipWithoutMock,
+				Config: testAccVMPowerConfig(`action = "start"
+  recreate_on_failure = true`),
+				Check: resource.TestCheckResourceAttrWith("vstack_vm.power", "id", func(value string) error {
+					failedID = value
+					return nil
+				}),
+			},
+			{
+				// A VM found in a failure status is replaced with recreate_on_failure.
+				SkipFunc: skipWithoutMock,
+				PreConfig: func() {
+					id, err := strconv.ParseInt(failedID, 10, 64)
+					if err != nil || !mockServer.FailVM(id, helper.Status.StartFailed, 3) {
+						t.Fatalf("failed to fail VM %q: %v", failedID, err)
+					}
+				},
+				Config: testAccVMPowerConfig(`action = "start"
+  recreate_on_failure = true`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith("vstack_vm.power", "id", func(value string) error {
+						if value == failedID {
+							return fmt.Errorf("expected the failed VM %s to be replaced", failedID)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+				),
+			},
+			{
+				// A new VM that fails to start is saved in the state, tainted.
+				SkipFunc:  skipWithoutMock,
+				PreConfig: func() { mockServer.FailStarts = true },
+				Taint:     []string{"vstack_vm.power"},
+				Config: testAccVMPowerConfig(`action = "start"
+  recreate_on_failure = true`),
+				ExpectError: regexp.MustCompile(`Error starting VM`),
+			},
+			{
+				// The next apply replaces the tainted VM.
+				SkipFunc:  skipWithoutMock,
+				PreConfig: func() { mockServer.FailStarts = false },
+				Config: testAccVMPowerConfig(`action = "start"
+  recreate_on_failure = true`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+					testAccCheckMockVMCount(1),
+				),
+			},
+		},
+	})
+}
+
+// TestAccVStackVM_allowRebootForUpdate tests changing the parameters of a running VM with and without
+// allow_reboot_for_update.
+func TestAccVStackVM_allowRebootForUpdate(t *testing.T) {
+	// Calls of the fake vStack API made before a step, see testAccCheckMockCalls.
+	var stops, restarts int
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVMPowerConfig(`action = "start"
+  cpu_priority = 10`),
+				Check: resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+			},
+			{
+				// The CPU priority of a running VM is changed live, without restarting it.
+				SkipFunc: skipWithoutMock,
+				PreConfig: func() {
+					stops = mockServer.Calls("vms-stop")
+					restarts = mockServer.Calls("vms-restart")
+				},
+				Config: testAccVMPowerConfig(`action = "start"
+  cpu_priority = 20`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_vm.power", "cpu_priority", "20"),
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+					testAccCheckMockCalls("vms-stop", &stops, 0),
+					testAccCheckMockCalls("vms-restart", &restarts, 0),
+				),
+			},
+			{
+				// The boot media of a running VM is not changed without allow_reboot_for_update.
+				SkipFunc: skipWithoutMock,
+				Config: testAccVMPowerConfig(`action = "start"
+  cpu_priority = 20
+  boot_media = 1`),
+				ExpectError: regexp.MustCompile(`VM must be restarted to apply the change`),
+			},
+			{
+				// With allow_reboot_for_update, the VM is stopped for the change and started again.
+				SkipFunc:  skipWithoutMock,
+				PreConfig: func() { stops = mockServer.Calls("vms-stop") },
+				Config: testAccVMPowerConfig(`action = "start"
+  cpu_priority = 20
+  boot_media = 1
+  allow_reboot_for_update = true`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_vm.power", "boot_media", "1"),
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+					testAccCheckMockCalls("vms-stop", &stops, 1),
+				),
+			},
+		},
+	})
+}
+
+// TestAccVStackVM_migrateOnUpdate tests migrating a VM to another node live and offline. The node IDs
+// are those of the fake vStack API, so its steps are skipped against a real cluster.
+func TestAccVStackVM_migrateOnUpdate(t *testing.T) {
+	// Calls of the fake vStack API made before a step, see testAccCheckMockCalls.
+	var stops int
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				SkipFunc: skipWithoutMock,
+				Config:   testAccVMPowerConfig(`action = "start"`),
+				Check:    resource.TestCheckResourceAttr("vstack_vm.power", "node", "1"),
+			},
+			{
+				// A running VM is migrated live to its new node.
+				SkipFunc:  skipWithoutMock,
+				PreConfig: func() { stops = mockServer.Calls("vms-stop") },
+				Config: testAccVMPowerConfig(`action = "start"
+  node = 2`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_vm.power", "node", "2"),
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+					testAccCheckMockCalls("vms-stop", &stops, 0),
+				),
+			},
+			{
+				// Without migrate_on_update, a running VM is only migrated offline if it may be restarted.
+				SkipFunc: skipWithoutMock,
+				Config: testAccVMPowerConfig(`action = "start"
+  node = 3
+  migrate_on_update = false`),
+				ExpectError: regexp.MustCompile(`VM must be restarted to migrate it`),
+			},
+			{
+				SkipFunc:  skipWithoutMock,
+				PreConfig: func() { stops = mockServer.Calls("vms-stop") },
+				Config: testAccVMPowerConfig(`action = "start"
+  node = 3
+  migrate_on_update = false
+  allow_reboot_for_update = true`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vstack_vm.power", "node", "3"),
+					resource.TestCheckResourceAttr("vstack_vm.power", "oper_status", "3"),
+					testAccCheckMockCalls("vms-stop", &stops, 1),
+				),
+			},
+		},
+	})
+}
+
+// testAccVMPowerConfig returns the configuration of a small VM with the given additional attributes.
+func testAccVMPowerConfig(attributes string) string {
+	return providerConfigTemplate + fmt.Sprintf(`
+resource "vstack_vm" "power" {
+  name       = "test-vm-power"
+  cpus       = 1
+  ram        = 2048
+  os_profile = var.os_profile
+  vdc_id     = var.vdc_id
+
+  %s
+
+  disks = [
+    {
+      size = 20
+      slot = 1
+    }
+  ]
+
+  guest = {
+    hostname = "test_vm_power"
+    users = {
+      root = {
+        ssh_authorized_keys = []
+        password            = "rootpassword"
+      }
+    }
+  }
+}
+`, attributes)
+}
+
+// testAccCheckMockCalls checks that the fake vStack API received want calls of a method
+// since *before was recorded, e.g. in the PreConfig of the step.
+func testAccCheckMockCalls(method string, before *int, want int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if got := mockServer.Calls(method) - *before; got != want {
+			return fmt.Errorf("expected %d %q calls, got %d", want, method, got)
+		}
+		return nil
+	}
+}
+
+// testAccCheckMockVMCount checks that the fake vStack API has want VMs, i.e. no failed VM was left behind.
+func testAccCheckMockVMCount(want int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if got := len(mockServer.VmIDs()); got != want {
+			return fmt.Errorf("expected %d VMs, got %d", want, got)
+		}
+		return nil
+	}
+}
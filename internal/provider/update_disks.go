@@ -8,7 +8,7 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"log"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"terraform-provider-vstack/internal/helper"
 	"terraform-provider-vstack/internal/models"
 	"terraform-provider-vstack/internal/vstack_api"
@@ -16,7 +16,7 @@ import (
 
 // UpdateDisks manages the synchronization of disk configurations between the Terraform plan and the actual VM state.
 // It handles adding new disks, updating existing ones, and removing disks that are no longer present in the plan.
-// The function utilizes helper functions for building JSON-RPC requests and handling API interactions.
+// The function utilizes the vStack API client for all API interactions.
 func (r *VstackVMResource) UpdateDisks(
 	ctx context.Context,
 	plan *models.VMResourceModel,
@@ -48,14 +48,14 @@ func (r *VstackVMResource) UpdateDisks(
 
 		if stateDisk, exists := stateDisksBySlot[slot]; exists {
 			// Disk exists in both state and plan: check for updates.
-			diskDiags := r.updateExistingDisk(state, disk, stateDisk)
+			diskDiags := r.updateExistingDisk(ctx, state, disk, stateDisk)
 			diags.Append(diskDiags...)
 			if diags.HasError() {
 				return diags
 			}
 		} else {
 			// Disk exists in plan but not in state: add it.
-			diskDiags := r.addNewDisk(state, disk)
+			diskDiags := r.addNewDisk(ctx, state, disk)
 			diags.Append(diskDiags...)
 			if diags.HasError() {
 				return diags
@@ -64,7 +64,7 @@ func (r *VstackVMResource) UpdateDisks(
 	}
 
 	// 5. Identify and remove disks that exist in state but are absent in the plan.
-	removeDiags := r.removeDisksNotInPlan(state, planDisksSlots)
+	removeDiags := r.removeDisksNotInPlan(ctx, state, planDisksSlots)
 	diags.Append(removeDiags...)
 	if diags.HasError() {
 		return diags
@@ -77,6 +77,7 @@ func (r *VstackVMResource) UpdateDisks(
 // It checks for changes in sector size, disk size, rate limits, and labels,
 // and performs the necessary API calls to synchronize the state.
 func (r *VstackVMResource) updateExistingDisk(
+	ctx context.Context,
 	state *models.VMResourceModel,
 	disk models.DiskModel,
 	stateDisk models.DiskModel,
@@ -98,104 +99,95 @@ func (r *VstackVMResource) updateExistingDisk(
 
 	// 2. Check if disk size needs to be increased.
 	if disk.Size.ValueInt64() > stateDisk.Size.ValueInt64() {
-		// Utilize the helper's BuildJSONRPCRequest to construct the payload.
-		reqPayload := helper.BuildJSONRPCRequest("vms-disk-resize", map[string]interface{}{
-			"id":        state.ID.ValueInt64(),
-			"disk_guid": stateDisk.GUID.ValueString(),
-			"size":      helper.ConvertGbToBytes(disk.Size.ValueInt64()),
-		})
-
 		// Execute the disk resize API call.
-		_, err := vstack_api.VmsDiskResize(reqPayload, r.AuthCookie, r.BaseURL, r.Client)
+		_, err := r.Client.VmsDiskResize(ctx, vstack_api.VmsDiskResizeParams{
+			ID:       state.ID.ValueInt64(),
+			DiskGUID: stateDisk.GUID.ValueString(),
+			Size:     helper.ConvertGbToBytes(disk.Size.ValueInt64()),
+		})
 		if err != nil {
 			diags.AddError("Error resizing disk", err.Error())
 			return diags
 		}
 
-		log.Printf("Successfully resized disk in slot %d to %d GB", slot, disk.Size.ValueInt64())
+		tflog.SubsystemInfo(ctx, logSubsystemVM, "Resized disk", map[string]any{"slot": slot, "size_gb": disk.Size.ValueInt64()})
 	}
 
 	// 3. Check and update rate limits if they have changed.
 	if disk.MbpsLimit.ValueInt64() != stateDisk.MbpsLimit.ValueInt64() ||
 		disk.IopsLimit.ValueInt64() != stateDisk.IopsLimit.ValueInt64() {
-		reqPayload := helper.BuildJSONRPCRequest("vm-ratelimit-disk", map[string]interface{}{
-			"vm_id":      state.ID.ValueInt64(),
-			"disk_guid":  stateDisk.GUID.ValueString(),
-			"mbps_limit": disk.MbpsLimit.ValueInt64(),
-			"iops_limit": disk.IopsLimit.ValueInt64(),
+		_, err := r.Client.VmRatelimitDisk(ctx, vstack_api.VmRatelimitDiskParams{
+			VmID:      state.ID.ValueInt64(),
+			DiskGUID:  stateDisk.GUID.ValueString(),
+			MBPSLimit: disk.MbpsLimit.ValueInt64(),
+			IOPSLimit: disk.IopsLimit.ValueInt64(),
 		})
-
-		_, err := vstack_api.VmRatelimitDisk(reqPayload, r.AuthCookie, r.BaseURL, r.Client)
 		if err != nil {
 			diags.AddError("Error updating disk rate limits", err.Error())
 			return diags
 		}
 
-		log.Printf("Successfully updated rate limits for disk in slot %d", slot)
+		tflog.SubsystemInfo(ctx, logSubsystemVM, "Updated disk rate limits", map[string]any{"slot": slot})
 	}
 
 	// 4. Check and update the disk label if it has changed.
 	if disk.Label.ValueString() != stateDisk.Label.ValueString() {
-		reqPayload := helper.BuildJSONRPCRequest("vm-disk-set-label", map[string]interface{}{
-			"vm_id": state.ID.ValueInt64(),
-			"guid":  stateDisk.GUID.ValueString(),
-			"label": disk.Label.ValueString(),
+		_, err := r.Client.VmDiskSetLabel(ctx, vstack_api.VmDiskSetLabelParams{
+			VmID:  state.ID.ValueInt64(),
+			GUID:  stateDisk.GUID.ValueString(),
+			Label: disk.Label.ValueString(),
 		})
-
-		_, err := vstack_api.VmDiskSetLabel(reqPayload, r.AuthCookie, r.BaseURL, r.Client)
 		if err != nil {
 			diags.AddError("Error updating disk label", err.Error())
 			return diags
 		}
 
-		log.Printf("Successfully updated label for disk in slot %d to '%s'", slot, disk.Label.ValueString())
+		tflog.SubsystemInfo(ctx, logSubsystemVM, "Updated disk label", map[string]any{"slot": slot, "label": disk.Label.ValueString()})
 	}
 
 	return diags
 }
 
 // addNewDisk handles adding a new disk based on the plan configuration.
-// It constructs the necessary JSON-RPC request using helper functions and performs the API call.
+// It constructs the typed request parameters and performs the API call.
 func (r *VstackVMResource) addNewDisk(
+	ctx context.Context,
 	state *models.VMResourceModel,
 	disk models.DiskModel,
 ) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	// Prepare sector size attributes, ensuring defaults are applied.
-	sectorSizeAttributes := map[string]interface{}{
-		"logical":  512,  // Default logical sector size in bytes
-		"physical": 4096, // Default physical sector size in bytes
+	sectorSize := vstack_api.SectorSizeParams{
+		Logical:  512,  // Default logical sector size in bytes
+		Physical: 4096, // Default physical sector size in bytes
 	}
 	if !disk.SectorSize.IsNull() {
 		attributes := disk.SectorSize.Attributes()
 		if logical, ok := attributes["logical"].(types.Int64); ok && !logical.IsNull() {
-			sectorSizeAttributes["logical"] = logical.ValueInt64()
+			sectorSize.Logical = logical.ValueInt64()
 		}
 		if physical, ok := attributes["physical"].(types.Int64); ok && !physical.IsNull() {
-			sectorSizeAttributes["physical"] = physical.ValueInt64()
+			sectorSize.Physical = physical.ValueInt64()
 		}
 	}
 
-	// Utilize the helper's BuildJSONRPCRequest to construct the payload.
-	reqPayload := helper.BuildJSONRPCRequest("vms-add-disk", map[string]interface{}{
-		"vm_id":       state.ID.ValueInt64(),
-		"size":        helper.ConvertGbToBytes(disk.Size.ValueInt64()), // Convert size from GB to bytes
-		"slot":        disk.Slot.ValueInt64(),
-		"label":       disk.Label.ValueString(),
-		"sector_size": sectorSizeAttributes,
-		"iops_limit":  disk.IopsLimit.ValueInt64(),
-		"mbps_limit":  disk.MbpsLimit.ValueInt64(),
-	})
-
 	// Execute the disk addition API call.
-	_, err := vstack_api.VmsAddDisk(reqPayload, r.AuthCookie, r.BaseURL, r.Client)
+	_, err := r.Client.VmsAddDisk(ctx, vstack_api.VmsAddDiskParams{
+		VmID:       state.ID.ValueInt64(),
+		Size:       helper.ConvertGbToBytes(disk.Size.ValueInt64()), // Convert size from GB to bytes
+		Slot:       disk.Slot.ValueInt64(),
+		Label:      disk.Label.ValueString(),
+		SectorSize: sectorSize,
+		IOPSLimit:  disk.IopsLimit.ValueInt64(),
+		MBPSLimit:  disk.MbpsLimit.ValueInt64(),
+	})
 	if err != nil {
 		diags.AddError("Error adding disk", err.Error())
 		return diags
 	}
 
-	log.Printf("Successfully added new disk in slot %d with label '%s'", disk.Slot.ValueInt64(), disk.Label.ValueString())
+	tflog.SubsystemInfo(ctx, logSubsystemVM, "Added disk", map[string]any{"slot": disk.Slot.ValueInt64(), "label": disk.Label.ValueString()})
 
 	return diags
 }
@@ -203,6 +195,7 @@ func (r *VstackVMResource) addNewDisk(
 // removeDisksNotInPlan identifies disks present in the state but absent in the plan and removes them.
 // It ensures that disks no longer defined in the Terraform configuration are deleted from the VM.
 func (r *VstackVMResource) removeDisksNotInPlan(
+	ctx context.Context,
 	state *models.VMResourceModel,
 	planDisksSlots map[int64]bool,
 ) diag.Diagnostics {
@@ -215,48 +208,36 @@ func (r *VstackVMResource) removeDisksNotInPlan(
 		if !planDisksSlots[slot] {
 			// 1. Stop the VM if it's currently running to safely remove the disk.
 			if state.OperStatus.ValueInt64() != helper.Status.Offline {
-				stopReq := helper.BuildJSONRPCRequest("vms-stop", map[string]interface{}{
-					"id": state.ID.ValueInt64(),
-				})
-
-				_, err := vstack_api.VmsStartStop(stopReq, r.AuthCookie, r.BaseURL, r.Client)
+				err := helper.PerformAction(ctx, r.Client, state.ID.ValueInt64(), "stop", r.StatusTimeout)
 				if err != nil {
 					diags.AddError("Error stopping VM before removing disk", err.Error())
 					return diags
 				}
 
-				log.Printf("Stopped VM ID %d to remove disk in slot %d", state.ID.ValueInt64(), slot)
+				tflog.SubsystemInfo(ctx, logSubsystemVM, "Stopped VM to remove disk", map[string]any{"vm_id": state.ID.ValueInt64(), "slot": slot})
 			}
 
-			// 2. Construct the JSON-RPC request to remove the disk.
-			removeReqPayload := helper.BuildJSONRPCRequest("vm-remove-disk", map[string]interface{}{
-				"vm_id":     state.ID.ValueInt64(),
-				"disk_guid": stateDisk.GUID.ValueString(),
+			// 2. Execute the disk removal API call.
+			_, err := r.Client.VmRemoveDisk(ctx, vstack_api.VmRemoveDiskParams{
+				VmID:     state.ID.ValueInt64(),
+				DiskGUID: stateDisk.GUID.ValueString(),
 			})
-
-			// 3. Execute the disk removal API call.
-			_, err := vstack_api.VmRemoveDisk(removeReqPayload, r.AuthCookie, r.BaseURL, r.Client)
 			if err != nil {
 				diags.AddError("Error removing disk", err.Error())
 				return diags
 			}
 
-			log.Printf("Successfully removed disk in slot %d from VM ID %d", slot, state.ID.ValueInt64())
+			tflog.SubsystemInfo(ctx, logSubsystemVM, "Removed disk", map[string]any{"vm_id": state.ID.ValueInt64(), "slot": slot})
 
-			// 4. Restart the VM if it was previously running.
+			// 3. Restart the VM if it was previously running.
 			if state.OperStatus.ValueInt64() != helper.Status.Offline {
-				restartReq := helper.BuildJSONRPCRequest("vms-restart", map[string]interface{}{
-
-					"id": state.ID.ValueInt64(),
-				})
-
-				_, err := vstack_api.VmsStartStop(restartReq, r.AuthCookie, r.BaseURL, r.Client)
+				err := helper.PerformAction(ctx, r.Client, state.ID.ValueInt64(), "start", r.StatusTimeout)
 				if err != nil {
 					diags.AddError("Error restarting VM after removing disk", err.Error())
 					return diags
 				}
 
-				log.Printf("Restarted VM ID %d after removing disk in slot %d", state.ID.ValueInt64(), slot)
+				tflog.SubsystemInfo(ctx, logSubsystemVM, "Restarted VM after removing disk", map[string]any{"vm_id": state.ID.ValueInt64(), "slot": slot})
 			}
 		}
 	}
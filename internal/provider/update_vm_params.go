@@ -0,0 +1,95 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// requiresPowerCycle reports whether the changed VM parameters can only be applied to a stopped VM.
+// CPUs, RAM and the CPU priority are changed live; vStack may still reject CPU and RAM changes of a running VM.
+func requiresPowerCycle(params vstack_api.VmSetVmParams) bool {
+	return params.BootMedia != nil || params.VcpuClass != nil
+}
+
+// UpdateVMParams applies the changed VM parameters with "vm-set".
+// Changes are applied live if possible; a running VM is only stopped and started again for changes that need it
+// if allow_reboot_for_update is set. If the plan stops the VM anyway, it is stopped before such changes instead.
+//
+// Parameters:
+// - ctx: The context of the operation.
+// - plan: The Terraform plan of the VM.
+// - vmID: The ID of the VM.
+// - params: The changed VM parameters.
+//
+// Returns:
+// - Diagnostics with an error if the parameters could not be applied or the VM would have to be restarted.
+func (r *VstackVMResource) UpdateVMParams(
+	ctx context.Context,
+	plan models.VMResourceModel,
+	vmID int64,
+	params vstack_api.VmSetVmParams,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+	powerCycle := requiresPowerCycle(params)
+
+	// 1. Stop the VM first if the plan stops it anyway, instead of restarting it for the change
+	action := strings.ToLower(plan.Action.ValueString())
+	if powerCycle && (action == "stop" || action == "shutdown" || action == "poweroff") {
+		running, err := helper.CheckIfVMIsRunning(ctx, r.Client, vmID)
+		if err != nil {
+			diags.AddError("Error checking VM status", err.Error())
+			return diags
+		}
+		if running {
+			if err := r.StopVM(ctx, vmID, action, plan.ShutdownTimeout); err != nil {
+				diags.AddError("Error stopping VM", vmFailureDetail(err))
+				return diags
+			}
+		}
+	}
+
+	// 2. Apply the change, restarting a running VM only if it needs it and it is allowed
+	opts := helper.HotplugOptions{
+		Hotplug:       !powerCycle,
+		RestartPolicy: helper.RestartPolicyNever,
+		StatusTimeout: r.StatusTimeout,
+	}
+	if plan.AllowRebootForUpdate.ValueBool() {
+		opts.RestartPolicy = helper.RestartPolicyIfNeeded
+	}
+	restarted, err := helper.ApplyHardwareChange(ctx, r.Client, vmID, opts, func(ctx context.Context) error {
+		_, setErr := r.Client.VmSet(ctx, vstack_api.VmSetParams{ID: vmID, VmParams: params})
+		return setErr
+	})
+
+	var forbiddenErr *helper.RestartForbiddenError
+	switch {
+	case errors.As(err, &forbiddenErr):
+		reason := "boot_media and vcpu_class can only be changed while the VM is stopped"
+		if forbiddenErr.HotplugError != nil {
+			reason = fmt.Sprintf("vStack rejected the change of the running VM: %s", forbiddenErr.HotplugError)
+		}
+		diags.AddError("VM must be restarted to apply the change",
+			fmt.Sprintf("VM %d is running and %s. Set allow_reboot_for_update = true to let the provider "+
+				"stop and start the VM, or stop it with action first.", vmID, reason))
+		return diags
+	case err != nil:
+		diags.AddError("Error updating VM parameters", vmFailureDetail(err))
+		return diags
+	}
+
+	if restarted {
+		tflog.SubsystemInfo(ctx, logSubsystemVM, "Restarted VM to apply the change of its parameters", map[string]any{"vm_id": vmID})
+	}
+	return diags
+}
@@ -0,0 +1,87 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// stringOneOfValidator checks that a string is one of a fixed set of values.
+type stringOneOfValidator struct {
+	values []string
+}
+
+var _ validator.String = stringOneOfValidator{}
+
+// stringOneOf returns a validator accepting only the given values.
+func stringOneOf(values ...string) stringOneOfValidator {
+	return stringOneOfValidator{values: values}
+}
+
+// Description returns a plain text description of the validator's behavior.
+func (v stringOneOfValidator) Description(_ context.Context) string {
+	quoted := make([]string, len(v.values))
+	for i, value := range v.values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return "value must be one of " + strings.Join(quoted, ", ")
+}
+
+// MarkdownDescription returns a markdown description of the validator's behavior.
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v stringOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for _, value := range v.values {
+		if req.ConfigValue.ValueString() == value {
+			return
+		}
+	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Invalid value",
+		fmt.Sprintf("%q: %s", req.ConfigValue.ValueString(), v.Description(ctx)))
+}
+
+// stringExcludesValidator checks that a string contains none of a set of characters.
+type stringExcludesValidator struct {
+	chars string
+}
+
+var _ validator.String = stringExcludesValidator{}
+
+// stringExcludes returns a validator rejecting strings containing any of the given characters.
+func stringExcludes(chars string) stringExcludesValidator {
+	return stringExcludesValidator{chars: chars}
+}
+
+// Description returns a plain text description of the validator's behavior.
+func (v stringExcludesValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must not contain any of %q", v.chars)
+}
+
+// MarkdownDescription returns a markdown description of the validator's behavior.
+func (v stringExcludesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v stringExcludesValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if strings.ContainsAny(req.ConfigValue.ValueString(), v.chars) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid value",
+			fmt.Sprintf("%q: %s", req.ConfigValue.ValueString(), v.Description(ctx)))
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/vstack_api"
+	"time"
+)
+
+// vmFailureDetail returns the detail of a diagnostic for an error of a VM operation.
+// For a VM in a failure status, it adds how to recover the VM from it.
+func vmFailureDetail(err error) string {
+	var status int64
+	var failedErr *helper.VMFailedError
+	var statusErr *helper.StatusFailedError
+	switch {
+	case errors.As(err, &failedErr):
+		status = failedErr.Status
+	case errors.As(err, &statusErr):
+		status = statusErr.Status
+	}
+
+	detail := err.Error()
+	if remediation := helper.FailureRemediation(status); remediation != "" {
+		detail += "\n\n" + remediation
+	}
+	return detail
+}
+
+// ModifyPlan plans the replacement of a VM found in a terminal failure status if recreate_on_failure is set.
+func (r *VstackVMResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to replace on create or destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var operStatus types.Int64
+	var recreateOnFailure types.Bool
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("oper_status"), &operStatus)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("recreate_on_failure"), &recreateOnFailure)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !recreateOnFailure.ValueBool() || !helper.IsFailedStatus(operStatus.ValueInt64()) {
+		return
+	}
+
+	// Terraform only replaces a resource if a value requiring the replacement changes.
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("oper_status"), types.Int64Unknown())...)
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("oper_status"))
+
+	tflog.SubsystemInfo(ctx, logSubsystemVM, "Planning the replacement of a failed VM", map[string]any{
+		"oper_status": helper.StatusName(operStatus.ValueInt64()),
+	})
+}
+
+// saveFailedVMTimeout bounds the lookup of a failed VM by saveFailedVMOnError.
+const saveFailedVMTimeout = time.Minute
+
+// saveFailedVMOnError calls SaveFailedVM if Create failed without saving the VM in the state.
+// Create often fails because its ctx has expired while waiting for the VM, so the VM is looked up
+// with a ctx of its own.
+func (r *VstackVMResource) saveFailedVMOnError(ctx context.Context, plan models.VMResourceModel, vmID int64, resp *resource.CreateResponse) {
+	if !resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), saveFailedVMTimeout)
+	defer cancel()
+	r.SaveFailedVM(ctx, plan, vmID, resp)
+}
+
+// SaveFailedVM saves a new VM that has entered a failure status during Create in the state,
+// so that Terraform taints it and the next apply replaces it instead of leaving it behind.
+// It does nothing if the VM is not in a failure status.
+func (r *VstackVMResource) SaveFailedVM(ctx context.Context, plan models.VMResourceModel, vmID int64, resp *resource.CreateResponse) {
+	apiResponse, err := r.Client.VmGet(ctx, vstack_api.VmGetParams{ID: vmID})
+	if err != nil || helper.CheckVMFailure(apiResponse) == nil {
+		return
+	}
+
+	state, err := helper.MapRespToState(apiResponse, plan)
+	if err != nil {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+	tflog.SubsystemWarn(ctx, logSubsystemVM, "Saved failed VM in the state to replace it on the next apply", map[string]any{
+		"vm_id":       vmID,
+		"oper_status": helper.StatusName(apiResponse.Data.OperStatus),
+	})
+}
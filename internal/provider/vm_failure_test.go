@@ -0,0 +1,66 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/models"
+	"terraform-provider-vstack/internal/vstack_api"
+	"terraform-provider-vstack/internal/vstacktest"
+)
+
+func TestSaveFailedVMOnErrorWithExpiredContext(t *testing.T) {
+	server := vstacktest.NewServer()
+	t.Cleanup(server.Close)
+
+	client := vstack_api.NewClient(server.URL, server.Client())
+	client.SetRetryPolicy(vstack_api.RetryPolicy{})
+	if err := client.Auth(context.Background(), vstack_api.AuthParams{Username: server.Username, Password: server.Password}); err != nil {
+		t.Fatalf("Auth: %v", err)
+	}
+	vm, err := client.VmCreate(context.Background(), vstack_api.VmCreateParams{Name: "vm", CPUs: 1, RAM: 1 << 30, OsProfile: "4001"})
+	if err != nil {
+		t.Fatalf("VmCreate: %v", err)
+	}
+	server.FailVM(vm.Data.ID, helper.Status.StartFailed, 3)
+
+	r := &VstackVMResource{Client: client}
+	var schemaResp resource.SchemaResponse
+	r.Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	resp := &resource.CreateResponse{State: tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(context.Background()), nil),
+	}}
+	resp.Diagnostics.AddError("Error starting VM", "context deadline exceeded")
+
+	timeoutsType, ok := schemaResp.Schema.Blocks["timeouts"].Type().(types.ObjectType)
+	if !ok {
+		t.Fatal("expected the timeouts block to be an object")
+	}
+	plan := models.VMResourceModel{Timeouts: types.ObjectNull(timeoutsType.AttrTypes)}
+
+	// Create has hit its timeout while waiting for the VM to start.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.saveFailedVMOnError(ctx, plan, vm.Data.ID, resp)
+
+	var state models.VMResourceModel
+	if resp.State.Raw.IsNull() {
+		t.Fatalf("expected the failed VM to be saved in the state, got %v", resp.Diagnostics)
+	}
+	if diags := resp.State.Get(context.Background(), &state); diags.HasError() {
+		t.Fatalf("State.Get: %v", diags)
+	}
+	if state.ID.ValueInt64() != vm.Data.ID || state.OperStatus.ValueInt64() != helper.Status.StartFailed {
+		t.Errorf("expected VM %d in StartFailed, got VM %d in %d", vm.Data.ID, state.ID.ValueInt64(), state.OperStatus.ValueInt64())
+	}
+}
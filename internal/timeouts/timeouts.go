@@ -0,0 +1,146 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+// Package timeouts provides the "timeouts" block of resources, following the schema of
+// terraform-plugin-framework-timeouts: a single nested block with optional "create", "read",
+// "update" and "delete" attributes holding Go duration strings.
+package timeouts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Names of the attributes of the timeouts block.
+const (
+	attributeNameCreate = "create"
+	attributeNameRead   = "read"
+	attributeNameUpdate = "update"
+	attributeNameDelete = "delete"
+)
+
+// Opts selects the operations for which the timeouts block accepts a timeout.
+type Opts struct {
+	Create bool
+	Read   bool
+	Update bool
+	Delete bool
+}
+
+// Block returns the "timeouts" block with an attribute for each operation enabled in opts.
+func Block(opts Opts) schema.Block {
+	attributes := map[string]schema.Attribute{}
+	for name, enabled := range map[string]bool{
+		attributeNameCreate: opts.Create,
+		attributeNameRead:   opts.Read,
+		attributeNameUpdate: opts.Update,
+		attributeNameDelete: opts.Delete,
+	} {
+		if !enabled {
+			continue
+		}
+		attributes[name] = schema.StringAttribute{
+			MarkdownDescription: fmt.Sprintf("Timeout of the %s operation, as a Go duration string (e.g. `30s`, `10m`).", name),
+			Optional:            true,
+			Validators:          []validator.String{PositiveDuration()},
+		}
+	}
+
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Timeouts of the resource operations.",
+		Attributes:          attributes,
+	}
+}
+
+// Create returns the create timeout from the timeouts block, or defaultTimeout if it is not set.
+func Create(ctx context.Context, value types.Object, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics) {
+	return get(ctx, value, attributeNameCreate, defaultTimeout)
+}
+
+// Read returns the read timeout from the timeouts block, or defaultTimeout if it is not set.
+func Read(ctx context.Context, value types.Object, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics) {
+	return get(ctx, value, attributeNameRead, defaultTimeout)
+}
+
+// Update returns the update timeout from the timeouts block, or defaultTimeout if it is not set.
+func Update(ctx context.Context, value types.Object, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics) {
+	return get(ctx, value, attributeNameUpdate, defaultTimeout)
+}
+
+// Delete returns the delete timeout from the timeouts block, or defaultTimeout if it is not set.
+func Delete(ctx context.Context, value types.Object, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics) {
+	return get(ctx, value, attributeNameDelete, defaultTimeout)
+}
+
+// get parses the named attribute of the timeouts block.
+func get(_ context.Context, value types.Object, name string, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if value.IsNull() || value.IsUnknown() {
+		return defaultTimeout, diags
+	}
+
+	raw, ok := value.Attributes()[name]
+	if !ok {
+		return defaultTimeout, diags
+	}
+
+	str, ok := raw.(types.String)
+	if !ok {
+		diags.AddAttributeError(path.Root("timeouts").AtName(name), "Invalid timeout",
+			fmt.Sprintf("Expected a string value, got %T.", raw))
+		return defaultTimeout, diags
+	}
+	if str.IsNull() || str.IsUnknown() {
+		return defaultTimeout, diags
+	}
+
+	duration, err := time.ParseDuration(str.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("timeouts").AtName(name), "Invalid timeout", err.Error())
+		return defaultTimeout, diags
+	}
+
+	return duration, diags
+}
+
+// positiveDurationValidator checks that a string is a positive Go duration.
+type positiveDurationValidator struct{}
+
+var _ validator.String = positiveDurationValidator{}
+
+// PositiveDuration returns a validator accepting only positive Go durations, e.g. "30s" or "168h".
+// Besides the timeouts block, it validates the other duration attributes of the provider.
+func PositiveDuration() validator.String {
+	return positiveDurationValidator{}
+}
+
+// Description returns a plain text description of the validator's behavior.
+func (v positiveDurationValidator) Description(_ context.Context) string {
+	return `value must be a positive Go duration, e.g. "30s" or "168h"`
+}
+
+// MarkdownDescription returns a markdown description of the validator's behavior.
+func (v positiveDurationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v positiveDurationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	duration, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil || duration <= 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid duration",
+			fmt.Sprintf("%q: %s", req.ConfigValue.ValueString(), v.Description(ctx)))
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package timeouts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var testAttrTypes = map[string]attr.Type{
+	attributeNameCreate: types.StringType,
+	attributeNameRead:   types.StringType,
+	attributeNameUpdate: types.StringType,
+	attributeNameDelete: types.StringType,
+}
+
+func TestCreateUsesConfiguredValue(t *testing.T) {
+	value := types.ObjectValueMust(testAttrTypes, map[string]attr.Value{
+		attributeNameCreate: types.StringValue("45m"),
+		attributeNameRead:   types.StringNull(),
+		attributeNameUpdate: types.StringNull(),
+		attributeNameDelete: types.StringNull(),
+	})
+
+	got, diags := Create(context.Background(), value, time.Minute)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got != 45*time.Minute {
+		t.Errorf("expected 45m, got %s", got)
+	}
+
+	got, diags = Delete(context.Background(), value, time.Minute)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got != time.Minute {
+		t.Errorf("expected the default for an unset attribute, got %s", got)
+	}
+}
+
+func TestReadUsesDefaultForNullBlock(t *testing.T) {
+	got, diags := Read(context.Background(), types.ObjectNull(testAttrTypes), 5*time.Minute)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got != 5*time.Minute {
+		t.Errorf("expected 5m, got %s", got)
+	}
+}
+
+func TestPositiveDuration(t *testing.T) {
+	for value, valid := range map[string]bool{"30s": true, "168h": true, "0s": false, "-1m": false, "soon": false} {
+		req := validator.StringRequest{ConfigValue: types.StringValue(value)}
+		var resp validator.StringResponse
+		PositiveDuration().ValidateString(context.Background(), req, &resp)
+		if resp.Diagnostics.HasError() == valid {
+			t.Errorf("%q: expected valid=%v, got diagnostics %v", value, valid, resp.Diagnostics)
+		}
+	}
+}
@@ -5,11 +5,26 @@ package vstack_api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// BaseJSONRPCRequest is the envelope shared by every JSON-RPC request sent to the vStack API.
+type BaseJSONRPCRequest struct {
+	ID      string      `json:"id"`      // A unique ID of the JSON-RPC request.
+	JsonRPC string      `json:"jsonrpc"` // The JSON-RPC protocol version.
+	Method  string      `json:"method"`  // The API method name, e.g. "vm-get".
+	Params  interface{} `json:"params"`  // The typed parameters of the method.
+}
+
 // BaseJSONRPCResponse contains the common fields for all JSON-RPC responses.
 // It serves as a base structure for unpacking the generic parts of the response.
 type BaseJSONRPCResponse struct {
@@ -32,64 +47,199 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("Error %d: %s", e.Code, e.Message)
 }
 
-// DoRequest sends a JSON-RPC request, unpacks the basic response structure,
-// and parses the "result" field into the provided resultContainer if needed.
-//
-// Parameters:
-// - requestPayload: The JSON-RPC request payload as a map.
-// - authCookie: The authentication cookie for the request.
-// - baseURL: The base URL of the API endpoint.
-// - client: The HTTP client used to send the request.
-// - resultContainer: A pointer to the struct where the "result" field will be unmarshaled.
-//
-// Returns:
-//   - error: An error object if the request fails, the response contains an error,
-//     or the "result" field cannot be decoded.
-func DoRequest(
-	requestPayload map[string]interface{},
-	authCookie string,
-	baseURL string,
-	client *http.Client,
-	resultContainer interface{}, // Pointer to the struct where "result" will be parsed.
-) error {
+// HTTPStatusError is returned when the API endpoint responds with an HTTP status
+// that cannot carry a JSON-RPC response, e.g. 401 Unauthorized or 502 Bad Gateway.
+type HTTPStatusError struct {
+	StatusCode int    // The HTTP status code of the response.
+	Status     string // The HTTP status line, e.g. "401 Unauthorized".
+}
+
+// Error implements the error interface for the HTTPStatusError struct.
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status: %s", e.Status)
+}
+
+// authErrorCodes lists the JSON-RPC error codes returned by vStack when the session is missing or expired.
+var authErrorCodes = map[int]bool{
+	401:    true,
+	403:    true,
+	-32001: true,
+}
+
+// IsAuthError reports whether err indicates that the APIEndpoint00 session is missing or has expired.
+func IsAuthError(err error) bool {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusUnauthorized
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		if authErrorCodes[apiErr.Code] {
+			return true
+		}
+		message := strings.ToLower(apiErr.Message)
+		return strings.Contains(message, "not authorized") ||
+			strings.Contains(message, "unauthorized") ||
+			strings.Contains(message, "session expired")
+	}
+
+	return false
+}
+
+// codeNotFound is the JSON-RPC error code returned by vStack when the requested object does not exist.
+const codeNotFound = 404
+
+// IsNotFound reports whether err is an error of vStack saying that the requested object does not exist.
+// Other errors of vStack, e.g. a held lock or a permission error, do not mean that the object is gone.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == codeNotFound
+}
+
+// NewJSONRPCRequest builds a JSON-RPC request envelope with a fresh ID for the specified method and parameters.
+func NewJSONRPCRequest(method string, params interface{}) BaseJSONRPCRequest {
+	return BaseJSONRPCRequest{
+		ID:      uuid.NewString(),
+		JsonRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	}
+}
+
+// send serializes and posts a JSON-RPC request to the API endpoint and decodes the basic response structure.
+// It returns the decoded response together with the cookies set by the server.
+// The request is bound to ctx, so cancelling ctx aborts the in-flight HTTP call.
+// Every call is logged with its method, JSON-RPC id and duration; payloads are logged at trace level
+// with sensitive values masked.
+func (c *Client) send(ctx context.Context, method string, params interface{}) (BaseJSONRPCResponse, []*http.Cookie, error) {
+	request := NewJSONRPCRequest(method, params)
+	fields := map[string]interface{}{
+		"method": method,
+		"id":     request.ID,
+	}
+	tflog.SubsystemTrace(ctx, LogSubsystem, "Sending JSON-RPC request", map[string]interface{}{
+		"method": method,
+		"id":     request.ID,
+		"params": redactPayload(params),
+	})
+
+	start := time.Now()
+	baseResp, cookies, err := c.post(ctx, request)
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, LogSubsystem, "JSON-RPC call failed", fields)
+		return BaseJSONRPCResponse{}, nil, err
+	}
+	if baseResp.Error != nil {
+		fields["error_code"] = baseResp.Error.Code
+	}
+	tflog.SubsystemDebug(ctx, LogSubsystem, "JSON-RPC call completed", fields)
+	tflog.SubsystemTrace(ctx, LogSubsystem, "Received JSON-RPC response", map[string]interface{}{
+		"method": method,
+		"id":     request.ID,
+		"result": redactPayload(baseResp.Result),
+	})
+
+	return baseResp, cookies, nil
+}
 
+// post performs the HTTP exchange of a JSON-RPC request.
+func (c *Client) post(ctx context.Context, request BaseJSONRPCRequest) (BaseJSONRPCResponse, []*http.Cookie, error) {
 	// 1. Serialize the request payload to JSON.
-	reqBody, err := json.Marshal(requestPayload)
+	reqBody, err := json.Marshal(request)
 	if err != nil {
-		return fmt.Errorf("DoRequest: error marshaling payload: %w", err)
+		return BaseJSONRPCResponse{}, nil, fmt.Errorf("error marshaling payload: %w", err)
 	}
 
 	// 2. Create a new HTTP POST request.
-	apiReq, err := http.NewRequest("POST", baseURL+"/.api/V4/.req/", bytes.NewBuffer(reqBody))
+	apiReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/.api/V4/.req/", bytes.NewBuffer(reqBody))
 	if err != nil {
-		return fmt.Errorf("DoRequest: error creating request: %w", err)
+		return BaseJSONRPCResponse{}, nil, fmt.Errorf("error creating request: %w", err)
+	}
+	apiReq.Header.Set("Content-Type", "application/json") // Set the content type to JSON.
+	if cookie := c.AuthCookie(); cookie != "" {
+		apiReq.Header.Set("X-Session-Auth", "APIEndpoint00="+cookie) // Set the authentication header.
 	}
-	apiReq.Header.Set("Content-Type", "application/json")            // Set the content type to JSON.
-	apiReq.Header.Set("X-Session-Auth", "APIEndpoint00="+authCookie) // Set the authentication header.
 
 	// 3. Execute the HTTP request.
-	apiResp, err := client.Do(apiReq)
+	apiResp, err := c.httpClient.Do(apiReq)
 	if err != nil {
-		return fmt.Errorf("DoRequest: HTTP error: %w", err)
+		return BaseJSONRPCResponse{}, nil, fmt.Errorf("HTTP error: %w", err)
 	}
 	defer func() {
 		if err := apiResp.Body.Close(); err != nil {
-			fmt.Printf("Error closing response body: %v\n", err)
+			tflog.SubsystemWarn(ctx, LogSubsystem, "Error closing response body", map[string]interface{}{"error": err.Error()})
 		}
 	}()
 
-	// 4. Decode the basic JSON-RPC response structure.
+	// 4. An expired session or an overloaded front-end may reject the request before it reaches the JSON-RPC layer.
+	if apiResp.StatusCode == http.StatusUnauthorized ||
+		apiResp.StatusCode == http.StatusTooManyRequests ||
+		apiResp.StatusCode >= http.StatusInternalServerError {
+		return BaseJSONRPCResponse{}, nil, &HTTPStatusError{StatusCode: apiResp.StatusCode, Status: apiResp.Status}
+	}
+
+	// 5. Decode the basic JSON-RPC response structure.
 	var baseResp BaseJSONRPCResponse
 	if err := json.NewDecoder(apiResp.Body).Decode(&baseResp); err != nil {
-		return fmt.Errorf("DoRequest: decode error: %w", err)
+		return BaseJSONRPCResponse{}, nil, fmt.Errorf("decode error: %w", err)
 	}
 
-	// 5. Check if the response contains an error.
-	if baseResp.Error != nil {
-		return fmt.Errorf("DoRequest: API error: %s", baseResp.Error.Error())
+	return baseResp, apiResp.Cookies(), nil
+}
+
+// DoRequest sends a JSON-RPC request, unpacks the basic response structure,
+// and parses the "result" field into the provided resultContainer if needed.
+// If the session has expired, the client re-authenticates and retries the request once.
+// Transient failures are retried with jittered exponential backoff according to the client's RetryPolicy.
+//
+// Parameters:
+// - ctx: The context of the request; cancelling it aborts the request.
+// - method: The JSON-RPC method name, e.g. "vm-get".
+// - params: The typed parameters of the method; it is serialized as the "params" field.
+// - resultContainer: A pointer to the struct where the "result" field will be unmarshaled.
+//
+// Returns:
+//   - error: An error object if the request fails, the response contains an error,
+//     or the "result" field cannot be decoded.
+func (c *Client) DoRequest(
+	ctx context.Context,
+	method string,
+	params interface{},
+	resultContainer interface{}, // Pointer to the struct where "result" will be parsed.
+) error {
+	ctx = withLogSubsystem(ctx)
+	retry := c.RetryPolicy()
+
+	var (
+		baseResp BaseJSONRPCResponse
+		err      error
+	)
+	for attempt := 0; ; attempt++ {
+		baseResp, err = c.callWithReauth(ctx, method, params)
+		if err == nil || attempt >= retry.MaxRetries || !IsRetryable(method, err) {
+			break
+		}
+
+		// Wait before the next attempt, giving up if the operation is cancelled.
+		wait := retry.backoff(attempt)
+		tflog.SubsystemInfo(ctx, LogSubsystem, "Retrying JSON-RPC request after a transient error", map[string]interface{}{
+			"method":  method,
+			"attempt": attempt + 1,
+			"wait_ms": wait.Milliseconds(),
+			"error":   err.Error(),
+		})
+		if sleepErr := sleepContext(ctx, wait); sleepErr != nil {
+			return fmt.Errorf("DoRequest: %w (retry aborted: %v)", err, sleepErr)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("DoRequest: %w", err)
 	}
 
-	// 6. If a resultContainer is provided, unmarshal the "result" field into it.
+	// If a resultContainer is provided, unmarshal the "result" field into it.
 	if resultContainer != nil {
 		if err := json.Unmarshal(baseResp.Result, resultContainer); err != nil {
 			return fmt.Errorf("DoRequest: error decoding result field: %w", err)
@@ -98,3 +248,42 @@ func DoRequest(
 
 	return nil
 }
+
+// callWithReauth sends a single JSON-RPC request. If the session has expired, it re-authenticates
+// with the stored credentials and sends the request once more.
+func (c *Client) callWithReauth(ctx context.Context, method string, params interface{}) (BaseJSONRPCResponse, error) {
+	// Remember the session the request was sent with, so that a concurrent re-authentication can be detected.
+	authCookie := c.AuthCookie()
+
+	baseResp, err := c.call(ctx, method, params)
+	if IsAuthError(err) && c.hasCredentials() {
+		tflog.SubsystemInfo(ctx, LogSubsystem, "Session expired, re-authenticating", map[string]interface{}{"method": method})
+		if authErr := c.reauthenticate(ctx, authCookie); authErr != nil {
+			return BaseJSONRPCResponse{}, fmt.Errorf("%w (re-authentication failed: %v)", err, authErr)
+		}
+		baseResp, err = c.call(ctx, method, params)
+	}
+
+	return baseResp, err
+}
+
+// call sends a single JSON-RPC request and returns the decoded response,
+// converting a JSON-RPC error or a transient result code in the response into an error.
+func (c *Client) call(ctx context.Context, method string, params interface{}) (BaseJSONRPCResponse, error) {
+	baseResp, _, err := c.send(ctx, method, params)
+	if err != nil {
+		return BaseJSONRPCResponse{}, err
+	}
+
+	// Check if the response contains an error.
+	if baseResp.Error != nil {
+		return BaseJSONRPCResponse{}, fmt.Errorf("API error: %w", baseResp.Error)
+	}
+
+	// Check if the result carries a code that denotes a temporary condition.
+	if err := checkResultCode(method, baseResp.Result); err != nil {
+		return BaseJSONRPCResponse{}, err
+	}
+
+	return baseResp, nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Client is a vStack JSON-RPC API client.
+// It owns the HTTP client, the base URL of the API endpoint and the authenticated session,
+// and is safe to share between all resources and data sources of the provider.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	retry      RetryPolicy
+
+	mu          sync.RWMutex
+	authCookie  string
+	credentials *AuthParams
+
+	// reauthMu serializes re-authentication, so that concurrent requests hitting an expired
+	// session trigger a single "auth" call.
+	reauthMu sync.Mutex
+}
+
+// NewClient creates a new API client for the specified base URL.
+// If httpClient is nil, a default http.Client is used. The client uses DefaultRetryPolicy.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	return &Client{
+		httpClient: httpClient,
+		baseURL:    baseURL,
+		retry:      DefaultRetryPolicy(),
+	}
+}
+
+// SetRetryPolicy replaces the retry policy of the client.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retry = policy
+}
+
+// RetryPolicy returns the retry policy of the client.
+func (c *Client) RetryPolicy() RetryPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.retry
+}
+
+// BaseURL returns the base URL of the API endpoint.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// AuthCookie returns the APIEndpoint00 session cookie of the current session.
+func (c *Client) AuthCookie() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.authCookie
+}
+
+// AuthParams represents the parameters of the "auth" method.
+type AuthParams struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// AuthResult represents the structure for the "result" field in the response to the "auth" method.
+type AuthResult struct {
+	Code    CodeUnion `json:"code"`
+	Message string    `json:"message"`
+	Data    struct {
+		Cookie map[string]string `json:"cookie"`
+	} `json:"data"`
+}
+
+// Auth sends an "auth" request and stores the received APIEndpoint00 session cookie in the client.
+// The credentials are remembered, so that the session can be renewed when it expires.
+func (c *Client) Auth(ctx context.Context, params AuthParams) error {
+	ctx = withLogSubsystem(ctx)
+	baseResp, cookies, err := c.send(ctx, "auth", params)
+	if err != nil {
+		return fmt.Errorf("Auth: %w", err)
+	}
+	if baseResp.Error != nil {
+		return fmt.Errorf("Auth: API error: %w", baseResp.Error)
+	}
+
+	var result AuthResult
+	if err := json.Unmarshal(baseResp.Result, &result); err != nil {
+		return fmt.Errorf("Auth: error decoding result field: %w", err)
+	}
+
+	// Check authentication result code.
+	if result.Code.CodeAsInt() != 1 {
+		return fmt.Errorf("Auth returned code=%s: %s", result.Code.CodeAsString(), result.Message)
+	}
+
+	// Store the received auth cookie, preferring the one from the response headers.
+	authCookie := result.Data.Cookie["APIEndpoint00"]
+	for _, cookie := range cookies {
+		if cookie.Name == "APIEndpoint00" {
+			authCookie = cookie.Value
+			break
+		}
+	}
+	if authCookie == "" {
+		return fmt.Errorf("Auth: no auth cookie received")
+	}
+
+	c.mu.Lock()
+	c.authCookie = authCookie
+	c.credentials = &params
+	c.mu.Unlock()
+
+	return nil
+}
+
+// hasCredentials reports whether the client has credentials to renew the session with.
+func (c *Client) hasCredentials() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.credentials != nil
+}
+
+// reauthenticate renews the session with the stored credentials.
+// staleCookie is the session cookie the failed request was sent with; if another request
+// has already renewed the session in the meantime, no new "auth" call is made.
+func (c *Client) reauthenticate(ctx context.Context, staleCookie string) error {
+	c.reauthMu.Lock()
+	defer c.reauthMu.Unlock()
+
+	if c.AuthCookie() != staleCookie {
+		return nil
+	}
+
+	c.mu.RLock()
+	credentials := *c.credentials
+	c.mu.RUnlock()
+
+	return c.Auth(ctx, credentials)
+}
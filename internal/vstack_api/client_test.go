@@ -0,0 +1,146 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// newAuthTestServer starts a JSON-RPC server that issues a new session cookie on every "auth" call
+// and answers "vm-get" only for the latest session.
+func newAuthTestServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		sessions int32
+		current  string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req BaseJSONRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch req.Method {
+		case "auth":
+			current = fmt.Sprintf("session-%d", atomic.AddInt32(&sessions, 1))
+			fmt.Fprintf(w, `{"id":%q,"jsonrpc":"2.0","result":{"code":1,"data":{"cookie":{"APIEndpoint00":%q}}}}`, req.ID, current)
+		case "vm-get":
+			if !strings.HasSuffix(r.Header.Get("X-Session-Auth"), "="+current) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintf(w, `{"id":%q,"jsonrpc":"2.0","result":{"code":1,"data":{"id":42}}}`, req.ID)
+		default:
+			fmt.Fprintf(w, `{"id":%q,"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found"}}`, req.ID)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &sessions
+}
+
+func TestClientReauthenticatesOnExpiredSession(t *testing.T) {
+	server, sessions := newAuthTestServer(t)
+
+	client := NewClient(server.URL, server.Client())
+	if err := client.Auth(context.Background(), AuthParams{Username: "user", Password: "password"}); err != nil {
+		t.Fatalf("Auth: %v", err)
+	}
+
+	// Simulate an expired session.
+	client.mu.Lock()
+	client.authCookie = "expired"
+	client.mu.Unlock()
+
+	resp, err := client.VmGet(context.Background(), VmGetParams{ID: 42})
+	if err != nil {
+		t.Fatalf("VmGet: %v", err)
+	}
+	if resp.Data.ID != 42 {
+		t.Errorf("unexpected VM ID: %d", resp.Data.ID)
+	}
+	if got := atomic.LoadInt32(sessions); got != 2 {
+		t.Errorf("expected 2 auth calls, got %d", got)
+	}
+}
+
+func TestClientReauthenticatesOnceForConcurrentRequests(t *testing.T) {
+	server, sessions := newAuthTestServer(t)
+
+	client := NewClient(server.URL, server.Client())
+	if err := client.Auth(context.Background(), AuthParams{Username: "user", Password: "password"}); err != nil {
+		t.Fatalf("Auth: %v", err)
+	}
+
+	client.mu.Lock()
+	client.authCookie = "expired"
+	client.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.VmGet(context.Background(), VmGetParams{ID: 42}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("VmGet: %v", err)
+	}
+	if got := atomic.LoadInt32(sessions); got != 2 {
+		t.Errorf("expected 2 auth calls, got %d", got)
+	}
+}
+
+func TestClientWithoutCredentialsDoesNotReauthenticate(t *testing.T) {
+	server, sessions := newAuthTestServer(t)
+
+	client := NewClient(server.URL, server.Client())
+	_, err := client.VmGet(context.Background(), VmGetParams{ID: 42})
+	if !IsAuthError(err) {
+		t.Fatalf("expected an auth error, got %v", err)
+	}
+	if got := atomic.LoadInt32(sessions); got != 0 {
+		t.Errorf("expected no auth calls, got %d", got)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("NetworkGet: %w", &Error{Code: 404, Message: "network 7 not found"}), true},
+		{&Error{Code: 409, Message: "VM is locked"}, false},
+		{&Error{Code: 403, Message: "permission denied"}, false},
+		{&HTTPStatusError{StatusCode: http.StatusNotFound, Status: "404 Not Found"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsNotFound(tt.err); got != tt.want {
+			t.Errorf("IsNotFound(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
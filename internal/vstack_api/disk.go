@@ -4,12 +4,29 @@
 package vstack_api
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 )
 
 // 1. vms-add-disk
 
+// VmsAddDiskParams represents the parameters of the "vms-add-disk" method.
+type VmsAddDiskParams struct {
+	VmID       int64            `json:"vm_id"`
+	Size       int64            `json:"size"` // Size of the disk in bytes.
+	Slot       int64            `json:"slot"`
+	Label      string           `json:"label"`
+	SectorSize SectorSizeParams `json:"sector_size"`
+	IOPSLimit  int64            `json:"iops_limit"`
+	MBPSLimit  int64            `json:"mbps_limit"`
+}
+
+// SectorSizeParams describes the logical and physical sector sizes of a new disk.
+type SectorSizeParams struct {
+	Logical  int64 `json:"logical"`
+	Physical int64 `json:"physical"`
+}
+
 // VmsAddDiskResult represents the structure for the "result" field in the response to the "vms-add-disk" method.
 type VmsAddDiskResult struct {
 	Code CodeUnion `json:"code"`
@@ -26,9 +43,9 @@ type VmsAddDiskResult struct {
 }
 
 // VmsAddDisk sends a "vms-add-disk" request and returns the result (GUID, SectorSize, etc.).
-func VmsAddDisk(requestPayload map[string]interface{}, authCookie string, baseURL string, client *http.Client) (VmsAddDiskResult, error) {
+func (c *Client) VmsAddDisk(ctx context.Context, params VmsAddDiskParams) (VmsAddDiskResult, error) {
 	var result VmsAddDiskResult
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vms-add-disk", params, &result); err != nil {
 		return VmsAddDiskResult{}, fmt.Errorf("VmsAddDisk: %w", err)
 	}
 	return result, nil
@@ -36,6 +53,13 @@ func VmsAddDisk(requestPayload map[string]interface{}, authCookie string, baseUR
 
 // 2. vms-disk-resize
 
+// VmsDiskResizeParams represents the parameters of the "vms-disk-resize" method.
+type VmsDiskResizeParams struct {
+	ID       int64  `json:"id"`
+	DiskGUID string `json:"disk_guid"`
+	Size     int64  `json:"size"` // New size of the disk in bytes.
+}
+
 // VmsDiskResizeResult represents the structure for the "result" field in the response to the "vms-disk-resize" method.
 type VmsDiskResizeResult struct {
 	Code CodeUnion `json:"code"`
@@ -46,9 +70,9 @@ type VmsDiskResizeResult struct {
 }
 
 // VmsDiskResize sends a "vms-disk-resize" request to change the disk size.
-func VmsDiskResize(requestPayload map[string]interface{}, authCookie string, baseURL string, client *http.Client) (VmsDiskResizeResult, error) {
+func (c *Client) VmsDiskResize(ctx context.Context, params VmsDiskResizeParams) (VmsDiskResizeResult, error) {
 	var result VmsDiskResizeResult
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vms-disk-resize", params, &result); err != nil {
 		return VmsDiskResizeResult{}, fmt.Errorf("VmsDiskResize: %w", err)
 	}
 	return result, nil
@@ -56,6 +80,12 @@ func VmsDiskResize(requestPayload map[string]interface{}, authCookie string, bas
 
 // 3. vm-remove-disk
 
+// VmRemoveDiskParams represents the parameters of the "vm-remove-disk" method.
+type VmRemoveDiskParams struct {
+	VmID     int64  `json:"vm_id"`
+	DiskGUID string `json:"disk_guid"`
+}
+
 // VmRemoveDiskResult represents the structure for the "result" field in the response to the "vm-remove-disk" method.
 type VmRemoveDiskResult struct {
 	Code CodeUnion `json:"code"`
@@ -65,9 +95,9 @@ type VmRemoveDiskResult struct {
 }
 
 // VmRemoveDisk removes a disk (by GUID or slot, etc.).
-func VmRemoveDisk(requestPayload map[string]interface{}, authCookie string, baseURL string, client *http.Client) (VmRemoveDiskResult, error) {
+func (c *Client) VmRemoveDisk(ctx context.Context, params VmRemoveDiskParams) (VmRemoveDiskResult, error) {
 	var result VmRemoveDiskResult
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vm-remove-disk", params, &result); err != nil {
 		return VmRemoveDiskResult{}, fmt.Errorf("VmRemoveDisk: %w", err)
 	}
 	return result, nil
@@ -75,6 +105,14 @@ func VmRemoveDisk(requestPayload map[string]interface{}, authCookie string, base
 
 // 4. vm-ratelimit-disk
 
+// VmRatelimitDiskParams represents the parameters of the "vm-ratelimit-disk" method.
+type VmRatelimitDiskParams struct {
+	VmID      int64  `json:"vm_id"`
+	DiskGUID  string `json:"disk_guid"`
+	MBPSLimit int64  `json:"mbps_limit"`
+	IOPSLimit int64  `json:"iops_limit"`
+}
+
 // VmRatelimitDiskResult represents the structure for the "result" field when calling "vm-ratelimit-disk".
 type VmRatelimitDiskResult struct {
 	Code CodeUnion `json:"code"`
@@ -84,9 +122,9 @@ type VmRatelimitDiskResult struct {
 }
 
 // VmRatelimitDisk sends a "vm-ratelimit-disk" request to set IOPS/MBPS limits on a disk.
-func VmRatelimitDisk(requestPayload map[string]interface{}, authCookie string, baseURL string, client *http.Client) (VmRatelimitDiskResult, error) {
+func (c *Client) VmRatelimitDisk(ctx context.Context, params VmRatelimitDiskParams) (VmRatelimitDiskResult, error) {
 	var result VmRatelimitDiskResult
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vm-ratelimit-disk", params, &result); err != nil {
 		return VmRatelimitDiskResult{}, fmt.Errorf("VmRatelimitDisk: %w", err)
 	}
 	return result, nil
@@ -94,6 +132,13 @@ func VmRatelimitDisk(requestPayload map[string]interface{}, authCookie string, b
 
 // 5. vm-disk-set-label
 
+// VmDiskSetLabelParams represents the parameters of the "vm-disk-set-label" method.
+type VmDiskSetLabelParams struct {
+	VmID  int64  `json:"vm_id"`
+	GUID  string `json:"guid"`
+	Label string `json:"label"`
+}
+
 // VmDiskSetLabelResult represents the structure for the "result" field when calling "vm-disk-set-label".
 type VmDiskSetLabelResult struct {
 	Code CodeUnion `json:"code"`
@@ -104,9 +149,9 @@ type VmDiskSetLabelResult struct {
 }
 
 // VmDiskSetLabel sends a "vm-disk-set-label" request to change the label of a disk.
-func VmDiskSetLabel(requestPayload map[string]interface{}, authCookie string, baseURL string, client *http.Client) (VmDiskSetLabelResult, error) {
+func (c *Client) VmDiskSetLabel(ctx context.Context, params VmDiskSetLabelParams) (VmDiskSetLabelResult, error) {
 	var result VmDiskSetLabelResult
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vm-disk-set-label", params, &result); err != nil {
 		return VmDiskSetLabelResult{}, fmt.Errorf("VmDiskSetLabel: %w", err)
 	}
 	return result, nil
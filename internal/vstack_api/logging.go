@@ -0,0 +1,91 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// LogSubsystem is the name of the tflog subsystem used for vStack API calls.
+const LogSubsystem = "vstack_api"
+
+// redactedValue replaces the values of sensitive keys in logged payloads.
+const redactedValue = "***"
+
+// SensitiveLogKeys lists the keys whose values are never logged, both as log fields and inside payloads,
+// e.g. the password of "auth", the session cookie and guest "users.*.password".
+var SensitiveLogKeys = []string{
+	"password",
+	"cookie",
+	"auth_cookie",
+	"apiendpoint00",
+	"x-session-auth",
+}
+
+// withLogSubsystem returns ctx with the vstack_api tflog subsystem, masking the sensitive fields.
+func withLogSubsystem(ctx context.Context) context.Context {
+	ctx = tflog.NewSubsystem(ctx, LogSubsystem)
+	return tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, LogSubsystem, SensitiveLogKeys...)
+}
+
+// isSensitiveLogKey reports whether the value of a payload key must be masked.
+func isSensitiveLogKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range SensitiveLogKeys {
+		if key == sensitive {
+			return true
+		}
+	}
+	return false
+}
+
+// redactPayload returns the JSON representation of a payload with the values of sensitive keys masked
+// at any depth. It is only meant for logging.
+func redactPayload(payload interface{}) string {
+	raw, ok := payload.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(payload); err != nil {
+			return "<unserializable payload>"
+		}
+	}
+
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return "<undecodable payload>"
+	}
+
+	redacted, err := json.Marshal(redactValue(decoded))
+	if err != nil {
+		return "<unserializable payload>"
+	}
+	return string(redacted)
+}
+
+// redactValue masks the values of sensitive keys in a decoded JSON value.
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if isSensitiveLogKey(key) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(nested)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = redactValue(nested)
+		}
+	}
+	return value
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestRedactPayloadMasksNestedSecrets(t *testing.T) {
+	payload := redactPayload(VmCreateParams{
+		Name: "vm",
+		Guest: &GuestParams{
+			Hostname: "vm",
+			Users: map[string]UserParams{
+				"root": {Password: "guest-secret", SSHAuthorizedKeys: []string{"ssh-ed25519 AAAA"}},
+			},
+		},
+	})
+
+	if strings.Contains(payload, "guest-secret") {
+		t.Errorf("guest password is not masked: %s", payload)
+	}
+	if !strings.Contains(payload, "ssh-ed25519 AAAA") {
+		t.Errorf("non-sensitive values must be kept: %s", payload)
+	}
+}
+
+func TestClientLogsCallsWithoutSecrets(t *testing.T) {
+	server, _ := newAuthTestServer(t)
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	client := NewClient(server.URL, server.Client())
+	if err := client.Auth(ctx, AuthParams{Username: "user", Password: "api-secret"}); err != nil {
+		t.Fatalf("Auth: %v", err)
+	}
+	if _, err := client.VmGet(ctx, VmGetParams{ID: 42}); err != nil {
+		t.Fatalf("VmGet: %v", err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("MultilineJSONDecode: %v", err)
+	}
+
+	var completed int
+	for _, entry := range entries {
+		if entry["@message"] != "JSON-RPC call completed" {
+			continue
+		}
+		completed++
+		for _, field := range []string{"method", "id", "duration_ms"} {
+			if _, ok := entry[field]; !ok {
+				t.Errorf("log entry has no %q field: %v", field, entry)
+			}
+		}
+	}
+	if completed != 2 {
+		t.Errorf("expected 2 completed calls in the log, got %d", completed)
+	}
+
+	for _, secret := range []string{"api-secret", "session-1"} {
+		if strings.Contains(output.String(), secret) {
+			t.Errorf("log output contains %q", secret)
+		}
+	}
+}
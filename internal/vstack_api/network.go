@@ -0,0 +1,208 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"context"
+	"fmt"
+)
+
+// Network types supported by vStack.
+const (
+	NetworkTypeVLAN  = "vlan"
+	NetworkTypeVXLAN = "vxlan"
+)
+
+// Network describes a VLAN or VXLAN network of a VDC.
+type Network struct {
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	VdcID       int64       `json:"vdc_id"`
+	Type        string      `json:"type"`    // NetworkTypeVLAN or NetworkTypeVXLAN.
+	Tag         int64       `json:"tag"`     // VLAN ID or VXLAN network identifier (VNI).
+	CIDR        string      `json:"cidr"`    // Subnet of the network, e.g. "10.0.0.0/24".
+	Gateway     string      `json:"gateway"` // Default gateway address; empty if the network has no gateway.
+	DHCP        NetworkDHCP `json:"dhcp"`
+}
+
+// NetworkDHCP describes the DHCP settings of a network.
+type NetworkDHCP struct {
+	Enabled    bool     `json:"enabled"`
+	RangeStart string   `json:"range_start,omitempty"` // First address leased by DHCP.
+	RangeEnd   string   `json:"range_end,omitempty"`   // Last address leased by DHCP.
+	DNSServers []string `json:"dns_servers,omitempty"` // DNS servers announced by DHCP.
+}
+
+// NetworkResult represents the structure for the "result" field in the responses to the
+// "networks-create" and "network-get" methods.
+type NetworkResult struct {
+	Code CodeUnion `json:"code"`
+	Data Network   `json:"data"`
+}
+
+// 1. networks-create
+
+// NetworksCreateParams represents the parameters of the "networks-create" method.
+type NetworksCreateParams struct {
+	VdcID       int64       `json:"vdc_id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Type        string      `json:"type"`
+	Tag         int64       `json:"tag,omitempty"` // vStack allocates a free tag if it is zero.
+	CIDR        string      `json:"cidr"`
+	Gateway     string      `json:"gateway,omitempty"`
+	DHCP        NetworkDHCP `json:"dhcp"`
+}
+
+// NetworksCreate sends a JSON-RPC "networks-create" request and returns the created network.
+//
+// Parameters:
+// - params: The VDC, name, type, addressing and DHCP settings of the network.
+//
+// Returns:
+// - NetworkResult: The result containing the created network.
+// - error: An error object if the request fails or the response code is unexpected.
+func (c *Client) NetworksCreate(ctx context.Context, params NetworksCreateParams) (NetworkResult, error) {
+	var result NetworkResult
+
+	if err := c.DoRequest(ctx, "networks-create", params, &result); err != nil {
+		return NetworkResult{}, fmt.Errorf("NetworksCreate: %w", err)
+	}
+
+	// Check the response code to ensure the network was created.
+	if result.Code.CodeAsInt() != 1 {
+		return result, fmt.Errorf("NetworksCreate: unexpected code=%s", result.Code.CodeAsString())
+	}
+
+	return result, nil
+}
+
+// 2. network-get
+
+// NetworkGetParams represents the parameters of the "network-get" method.
+type NetworkGetParams struct {
+	ID int64 `json:"id"`
+}
+
+// NetworkGet sends a JSON-RPC "network-get" request and returns the network.
+func (c *Client) NetworkGet(ctx context.Context, params NetworkGetParams) (NetworkResult, error) {
+	var result NetworkResult
+
+	if err := c.DoRequest(ctx, "network-get", params, &result); err != nil {
+		return NetworkResult{}, fmt.Errorf("NetworkGet: %w", err)
+	}
+
+	if result.Code.CodeAsInt() != 1 {
+		return result, fmt.Errorf("NetworkGet: unexpected code=%s", result.Code.CodeAsString())
+	}
+
+	return result, nil
+}
+
+// 3. networks-list
+
+// NetworksListParams represents the parameters of the "networks-list" method.
+type NetworksListParams struct {
+	VdcID int64 `json:"vdc_id,omitempty"` // Lists the networks of all VDCs if it is zero.
+}
+
+// NetworksListResult represents the structure for the "result" field in the response to the "networks-list" method.
+type NetworksListResult struct {
+	Code CodeUnion `json:"code"`
+	Data []Network `json:"data"`
+}
+
+// NetworksList sends a JSON-RPC "networks-list" request and returns the networks visible to the user.
+func (c *Client) NetworksList(ctx context.Context, params NetworksListParams) (NetworksListResult, error) {
+	var result NetworksListResult
+
+	if err := c.DoRequest(ctx, "networks-list", params, &result); err != nil {
+		return NetworksListResult{}, fmt.Errorf("NetworksList: %w", err)
+	}
+
+	if result.Code.CodeAsInt() != 1 {
+		return result, fmt.Errorf("NetworksList: unexpected code=%s", result.Code.CodeAsString())
+	}
+
+	return result, nil
+}
+
+// 4. network-set
+
+// NetworkSetParams represents the parameters of the "network-set" method.
+type NetworkSetParams struct {
+	ID            int64                   `json:"id"`
+	NetworkParams NetworkSetNetworkParams `json:"network_params"`
+}
+
+// NetworkSetNetworkParams holds the network parameters to be changed by "network-set".
+// Only non-nil fields are sent to the API. The VDC, type, tag and CIDR of a network cannot be changed.
+type NetworkSetNetworkParams struct {
+	Name        *string      `json:"name,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	Gateway     *string      `json:"gateway,omitempty"`
+	DHCP        *NetworkDHCP `json:"dhcp,omitempty"`
+}
+
+// NetworkSetResult represents the structure for the "result" field in the response to the "network-set" method.
+type NetworkSetResult struct {
+	Code CodeUnion `json:"code"`
+	Data struct {
+		Message string `json:"message,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// NetworkSet sends a JSON-RPC "network-set" request and parses the result.
+//
+// Parameters:
+// - params: The ID of the network and the network parameters to be changed.
+//
+// Returns:
+// - NetworkSetResult: The result containing the response message.
+// - error: An error object if the request fails or the response code is unexpected.
+func (c *Client) NetworkSet(ctx context.Context, params NetworkSetParams) (NetworkSetResult, error) {
+	var result NetworkSetResult
+
+	if err := c.DoRequest(ctx, "network-set", params, &result); err != nil {
+		return NetworkSetResult{}, fmt.Errorf("NetworkSet: %w", err)
+	}
+
+	if result.Code.CodeAsInt() != 1 {
+		return result, fmt.Errorf("NetworkSet: unexpected code=%s", result.Code.CodeAsString())
+	}
+
+	return result, nil
+}
+
+// 5. networks-remove
+
+// NetworksRemoveParams represents the parameters of the "networks-remove" method.
+type NetworksRemoveParams struct {
+	ID int64 `json:"id"`
+}
+
+// NetworksRemoveResult represents the structure for the "result" field in the response to the "networks-remove" method.
+type NetworksRemoveResult struct {
+	Code CodeUnion `json:"code"`
+	Data struct {
+		Message string `json:"message"`
+	} `json:"data,omitempty"`
+}
+
+// NetworksRemove sends a JSON-RPC "networks-remove" request. vStack rejects the removal of a network
+// that still has NICs attached.
+func (c *Client) NetworksRemove(ctx context.Context, params NetworksRemoveParams) (NetworksRemoveResult, error) {
+	var result NetworksRemoveResult
+
+	if err := c.DoRequest(ctx, "networks-remove", params, &result); err != nil {
+		return NetworksRemoveResult{}, fmt.Errorf("NetworksRemove: %w", err)
+	}
+
+	if result.Code.CodeAsInt() != 1 {
+		return result, fmt.Errorf("NetworksRemove: unexpected code=%s", result.Code.CodeAsString())
+	}
+
+	return result, nil
+}
@@ -4,12 +4,23 @@
 package vstack_api
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 )
 
 // 1. vms-add-nic
 
+// VmsAddNicParams represents the parameters of the "vms-add-nic" method.
+type VmsAddNicParams struct {
+	ID             int64  `json:"id"`
+	NetworkID      int64  `json:"network_id"`
+	Slot           int64  `json:"slot"`
+	RatelimitMBits *int64 `json:"ratelimit_mbits,omitempty"`
+	Address        string `json:"address,omitempty"`
+	IPGuard        int64  `json:"ip_guard,omitempty"`
+	MAC            string `json:"mac,omitempty"` // MAC address to assign; vStack generates one if it is empty.
+}
+
 // VmsAddNicResult represents the structure for the "result" field in the response to the "vms-add-nic" method.
 type VmsAddNicResult struct {
 	Code CodeUnion `json:"code"`
@@ -25,11 +36,11 @@ type VmsAddNicResult struct {
 }
 
 // VmsAddNic sends a JSON-RPC "vms-add-nic" request and parses the result.
-func VmsAddNic(requestPayload map[string]interface{}, authCookie string, baseURL string, client *http.Client) (VmsAddNicResult, error) {
+func (c *Client) VmsAddNic(ctx context.Context, params VmsAddNicParams) (VmsAddNicResult, error) {
 	var result VmsAddNicResult
 
 	// Use our DoRequest
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vms-add-nic", params, &result); err != nil {
 		return VmsAddNicResult{}, fmt.Errorf("VmsAddNic: DoRequest error: %w", err)
 	}
 
@@ -38,6 +49,12 @@ func VmsAddNic(requestPayload map[string]interface{}, authCookie string, baseURL
 
 // 2. vm-remove-nic
 
+// VmRemoveNicParams represents the parameters of the "vm-remove-nic" method.
+type VmRemoveNicParams struct {
+	VmID   int64 `json:"vm_id"`
+	PortID int64 `json:"port_id"`
+}
+
 // VmRemoveNicResult represents the structure for the "result" field in the response to the "vm-remove-nic" method.
 type VmRemoveNicResult struct {
 	Code CodeUnion `json:"code"`
@@ -47,10 +64,10 @@ type VmRemoveNicResult struct {
 }
 
 // VmRemoveNic removes a NIC.
-func VmRemoveNic(requestPayload map[string]interface{}, authCookie string, baseURL string, client *http.Client) (VmRemoveNicResult, error) {
+func (c *Client) VmRemoveNic(ctx context.Context, params VmRemoveNicParams) (VmRemoveNicResult, error) {
 	var result VmRemoveNicResult
 
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vm-remove-nic", params, &result); err != nil {
 		return VmRemoveNicResult{}, fmt.Errorf("VmRemoveNic: %w", err)
 	}
 
@@ -59,6 +76,13 @@ func VmRemoveNic(requestPayload map[string]interface{}, authCookie string, baseU
 
 // 3. vm-ratelimit-nic
 
+// VmRatelimitNicParams represents the parameters of the "vm-ratelimit-nic" method.
+type VmRatelimitNicParams struct {
+	VmID           int64 `json:"vm_id"`
+	PortID         int64 `json:"port_id"`
+	RatelimitMBits int64 `json:"ratelimit_mbits"`
+}
+
 // VmRatelimitNicResult represents the structure for the "result" field when calling "vm-ratelimit-nic".
 type VmRatelimitNicResult struct {
 	Code CodeUnion `json:"code"`
@@ -68,10 +92,10 @@ type VmRatelimitNicResult struct {
 }
 
 // VmRatelimitNic sets the ratelimit on a NIC.
-func VmRatelimitNic(requestPayload map[string]interface{}, authCookie string, baseURL string, client *http.Client) (VmRatelimitNicResult, error) {
+func (c *Client) VmRatelimitNic(ctx context.Context, params VmRatelimitNicParams) (VmRatelimitNicResult, error) {
 	var result VmRatelimitNicResult
 
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vm-ratelimit-nic", params, &result); err != nil {
 		return VmRatelimitNicResult{}, fmt.Errorf("VmRatelimitNic: %w", err)
 	}
 
@@ -0,0 +1,178 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Default retry settings used when the provider configuration does not set them.
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryMinWait = 1 * time.Second
+	DefaultRetryMaxWait = 30 * time.Second
+)
+
+// RetryPolicy controls how failed API requests are retried.
+type RetryPolicy struct {
+	MaxRetries int           // Maximum number of retries after the first attempt; 0 disables retries.
+	MinWait    time.Duration // Backoff before the first retry.
+	MaxWait    time.Duration // Upper bound of the backoff between two attempts.
+}
+
+// DefaultRetryPolicy returns the retry policy used by a new client.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		MinWait:    DefaultRetryMinWait,
+		MaxWait:    DefaultRetryMaxWait,
+	}
+}
+
+// backoff returns the jittered exponential delay before the retry following the given attempt (starting at 0).
+// The delay is chosen uniformly from the upper half of the exponential window, so that concurrent
+// resource operations do not retry in lockstep.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.MinWait
+	for i := 0; i < attempt && wait < p.MaxWait; i++ {
+		wait *= 2
+	}
+	if wait > p.MaxWait {
+		wait = p.MaxWait
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	half := wait / 2
+	return half + rand.N(wait-half+1)
+}
+
+// ResultCodeError is returned when the "result" of a response carries a code that vStack uses for
+// temporary conditions, e.g. a VM that is locked by another operation.
+type ResultCodeError struct {
+	Method string    // The API method that returned the code.
+	Code   CodeUnion // The code of the result.
+}
+
+// Error implements the error interface for the ResultCodeError struct.
+func (e *ResultCodeError) Error() string {
+	return fmt.Sprintf("%s returned transient code=%s", e.Method, e.Code.CodeAsString())
+}
+
+// idempotentMethods lists the API methods that only read data and can safely be sent again
+// even if the previous attempt may have reached the server.
+var idempotentMethods = map[string]bool{
+	"vm-get":            true,
+	"vm-profiles":       true,
+	"network-get":       true,
+	"networks-list":     true,
+	"vms-list":          true,
+	"vm-snapshots-list": true,
+}
+
+// transientErrorCodes lists the JSON-RPC error codes returned when vStack temporarily rejects a request
+// (resource locked, service unavailable, too many requests). Such requests were not applied.
+var transientErrorCodes = map[int]bool{
+	423: true,
+	429: true,
+	503: true,
+}
+
+// transientResultCodes lists the non-1 result codes that vStack returns for temporary conditions.
+var transientResultCodes = map[int64]bool{
+	423: true,
+	503: true,
+}
+
+// transientMessages lists fragments of JSON-RPC error messages that denote a temporary condition.
+var transientMessages = []string{
+	"locked",
+	"busy",
+	"try again",
+	"temporarily unavailable",
+}
+
+// IsRetryable reports whether a request of the given method that failed with err may be sent again.
+//
+// Requests explicitly rejected by vStack as temporarily impossible are retried for every method,
+// since they have not been applied. Network errors and 5xx responses are only retried for
+// idempotent methods, because the request may have been applied before the failure.
+func IsRetryable(method string, err error) bool {
+	// A cancelled operation is never retried. A deadline may stem from the per-request read timeout,
+	// so it is classified like any other network error; an expired operation context stops the
+	// retry loop while waiting for the next attempt.
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		if transientErrorCodes[apiErr.Code] {
+			return true
+		}
+		message := strings.ToLower(apiErr.Message)
+		for _, fragment := range transientMessages {
+			if strings.Contains(message, fragment) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var codeErr *ResultCodeError
+	if errors.As(err, &codeErr) {
+		return true
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.StatusCode == http.StatusTooManyRequests {
+			return true
+		}
+		return statusErr.StatusCode >= http.StatusInternalServerError && idempotentMethods[method]
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return idempotentMethods[method]
+	}
+
+	return false
+}
+
+// checkResultCode returns a ResultCodeError if the raw "result" carries a transient code.
+func checkResultCode(method string, result []byte) error {
+	var probe struct {
+		Code *CodeUnion `json:"code"`
+	}
+	if err := json.Unmarshal(result, &probe); err != nil || probe.Code == nil {
+		return nil
+	}
+	if transientResultCodes[probe.Code.CodeAsInt()] {
+		return &ResultCodeError{Method: method, Code: *probe.Code}
+	}
+	return nil
+}
+
+// sleepContext waits for the specified duration or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFlakyTestServer starts a JSON-RPC server that fails the first failures requests using fail
+// and answers successfully afterwards.
+func newFlakyTestServer(t *testing.T, failures int32, fail func(w http.ResponseWriter, id string)) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req BaseJSONRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if atomic.AddInt32(&calls, 1) <= failures {
+			fail(w, req.ID)
+			return
+		}
+		fmt.Fprintf(w, `{"id":%q,"jsonrpc":"2.0","result":{"code":1,"data":{"id":42}}}`, req.ID)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+// newTestClient creates a client with short backoff delays.
+func newTestClient(server *httptest.Server, maxRetries int) *Client {
+	client := NewClient(server.URL, server.Client())
+	client.SetRetryPolicy(RetryPolicy{
+		MaxRetries: maxRetries,
+		MinWait:    time.Millisecond,
+		MaxWait:    5 * time.Millisecond,
+	})
+	return client
+}
+
+func badGateway(w http.ResponseWriter, _ string) {
+	http.Error(w, "<html>502 Bad Gateway</html>", http.StatusBadGateway)
+}
+
+func vmLocked(w http.ResponseWriter, id string) {
+	fmt.Fprintf(w, `{"id":%q,"jsonrpc":"2.0","error":{"code":423,"message":"VM is locked"}}`, id)
+}
+
+func TestDoRequestRetriesIdempotentMethodOnBadGateway(t *testing.T) {
+	server, calls := newFlakyTestServer(t, 2, badGateway)
+	client := newTestClient(server, 3)
+
+	resp, err := client.VmGet(context.Background(), VmGetParams{ID: 42})
+	if err != nil {
+		t.Fatalf("VmGet: %v", err)
+	}
+	if resp.Data.ID != 42 {
+		t.Errorf("unexpected VM ID: %d", resp.Data.ID)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("expected 3 calls, got %d", got)
+	}
+}
+
+func TestDoRequestRetriesNetworkGetOnBadGateway(t *testing.T) {
+	server, calls := newFlakyTestServer(t, 1, badGateway)
+	client := newTestClient(server, 3)
+
+	if _, err := client.NetworkGet(context.Background(), NetworkGetParams{ID: 42}); err != nil {
+		t.Fatalf("NetworkGet: %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("expected 2 calls, got %d", got)
+	}
+}
+
+func TestDoRequestDoesNotRetryMutatingMethodOnBadGateway(t *testing.T) {
+	server, calls := newFlakyTestServer(t, 1, badGateway)
+	client := newTestClient(server, 3)
+
+	if _, err := client.VmRemove(context.Background(), VmRemoveParams{ID: 42, VdcID: 1}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("expected 1 call, got %d", got)
+	}
+}
+
+func TestDoRequestRetriesMutatingMethodOnLockedVM(t *testing.T) {
+	server, calls := newFlakyTestServer(t, 1, vmLocked)
+	client := newTestClient(server, 3)
+
+	if _, err := client.VmRemove(context.Background(), VmRemoveParams{ID: 42, VdcID: 1}); err != nil {
+		t.Fatalf("VmRemove: %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("expected 2 calls, got %d", got)
+	}
+}
+
+func TestDoRequestGivesUpAfterMaxRetries(t *testing.T) {
+	server, calls := newFlakyTestServer(t, 10, vmLocked)
+	client := newTestClient(server, 2)
+
+	if _, err := client.VmGet(context.Background(), VmGetParams{ID: 42}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("expected 3 calls, got %d", got)
+	}
+}
+
+func TestDoRequestStopsRetryingWhenCancelled(t *testing.T) {
+	server, calls := newFlakyTestServer(t, 10, vmLocked)
+	client := NewClient(server.URL, server.Client())
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 5, MinWait: time.Hour, MaxWait: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.VmGet(ctx, VmGetParams{ID: 42}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("expected 1 call, got %d", got)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MinWait: time.Second, MaxWait: 10 * time.Second}
+
+	for attempt, window := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		wait := policy.backoff(attempt)
+		if wait < window/2 || wait > window {
+			t.Errorf("attempt %d: backoff %s outside of [%s, %s]", attempt, wait, window/2, window)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"context"
+	"fmt"
+)
+
+// VmSnapshot describes a snapshot of a dataset of a VM, normally its root dataset.
+type VmSnapshot struct {
+	Name        string `json:"name"` // Unique within the dataset.
+	VmID        int64  `json:"vm_id"`
+	Dataset     string `json:"dataset"`
+	Description string `json:"description"`
+	Created     int64  `json:"created"` // Unix time the snapshot was taken at.
+	Expires     int64  `json:"expires"` // Unix time vStack removes the snapshot at; zero if it is kept until removed.
+	Used        int64  `json:"used"`    // Space used by the snapshot, in bytes.
+}
+
+// 1. vm-snapshots-create
+
+// VmSnapshotsCreateParams represents the parameters of the "vm-snapshots-create" method.
+type VmSnapshotsCreateParams struct {
+	ID          int64  `json:"id"` // ID of the VM.
+	Dataset     string `json:"dataset"`
+	Name        string `json:"name,omitempty"` // vStack generates a name if it is empty.
+	Description string `json:"description,omitempty"`
+	Retention   int64  `json:"retention,omitempty"` // Seconds until vStack removes the snapshot; zero keeps it.
+}
+
+// VmSnapshotResult represents the structure for the "result" field in the response to the "vm-snapshots-create" method.
+type VmSnapshotResult struct {
+	Code CodeUnion  `json:"code"`
+	Data VmSnapshot `json:"data"`
+}
+
+// VmSnapshotsCreate sends a JSON-RPC "vm-snapshots-create" request and returns the created snapshot.
+//
+// Parameters:
+// - params: The VM, the dataset to snapshot and the name, description and retention of the snapshot.
+//
+// Returns:
+// - VmSnapshotResult: The result containing the created snapshot.
+// - error: An error object if the request fails or the response code is unexpected.
+func (c *Client) VmSnapshotsCreate(ctx context.Context, params VmSnapshotsCreateParams) (VmSnapshotResult, error) {
+	var result VmSnapshotResult
+
+	if err := c.DoRequest(ctx, "vm-snapshots-create", params, &result); err != nil {
+		return VmSnapshotResult{}, fmt.Errorf("VmSnapshotsCreate: %w", err)
+	}
+
+	// Check the response code to ensure the snapshot was taken.
+	if result.Code.CodeAsInt() != 1 {
+		return result, fmt.Errorf("VmSnapshotsCreate: unexpected code=%s", result.Code.CodeAsString())
+	}
+
+	return result, nil
+}
+
+// 2. vm-snapshots-list
+
+// VmSnapshotsListParams represents the parameters of the "vm-snapshots-list" method.
+type VmSnapshotsListParams struct {
+	ID      int64  `json:"id"`                // ID of the VM.
+	Dataset string `json:"dataset,omitempty"` // Lists the snapshots of all datasets of the VM if it is empty.
+}
+
+// VmSnapshotsListResult represents the structure for the "result" field in the response to the "vm-snapshots-list" method.
+type VmSnapshotsListResult struct {
+	Code CodeUnion    `json:"code"`
+	Data []VmSnapshot `json:"data"`
+}
+
+// VmSnapshotsList sends a JSON-RPC "vm-snapshots-list" request and returns the snapshots of the VM.
+// Expired snapshots are not listed.
+func (c *Client) VmSnapshotsList(ctx context.Context, params VmSnapshotsListParams) (VmSnapshotsListResult, error) {
+	var result VmSnapshotsListResult
+
+	if err := c.DoRequest(ctx, "vm-snapshots-list", params, &result); err != nil {
+		return VmSnapshotsListResult{}, fmt.Errorf("VmSnapshotsList: %w", err)
+	}
+
+	if result.Code.CodeAsInt() != 1 {
+		return result, fmt.Errorf("VmSnapshotsList: unexpected code=%s", result.Code.CodeAsString())
+	}
+
+	return result, nil
+}
+
+// 3. vm-snapshots-remove
+
+// VmSnapshotsRemoveParams represents the parameters of the "vm-snapshots-remove" method.
+type VmSnapshotsRemoveParams struct {
+	ID      int64  `json:"id"` // ID of the VM.
+	Dataset string `json:"dataset"`
+	Name    string `json:"name"`
+}
+
+// VmSnapshotsRemoveResult represents the structure for the "result" field in the response to the "vm-snapshots-remove" method.
+type VmSnapshotsRemoveResult struct {
+	Code CodeUnion `json:"code"`
+	Data struct {
+		Message string `json:"message"`
+	} `json:"data,omitempty"`
+}
+
+// VmSnapshotsRemove sends a JSON-RPC "vm-snapshots-remove" request.
+func (c *Client) VmSnapshotsRemove(ctx context.Context, params VmSnapshotsRemoveParams) (VmSnapshotsRemoveResult, error) {
+	var result VmSnapshotsRemoveResult
+
+	if err := c.DoRequest(ctx, "vm-snapshots-remove", params, &result); err != nil {
+		return VmSnapshotsRemoveResult{}, fmt.Errorf("VmSnapshotsRemove: %w", err)
+	}
+
+	if result.Code.CodeAsInt() != 1 {
+		return result, fmt.Errorf("VmSnapshotsRemove: unexpected code=%s", result.Code.CodeAsString())
+	}
+
+	return result, nil
+}
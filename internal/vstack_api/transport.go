@@ -0,0 +1,138 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Default timeouts used when the provider configuration does not set them.
+const (
+	DefaultConnectTimeout = 30 * time.Second
+	DefaultReadTimeout    = 5 * time.Minute
+)
+
+// TransportConfig describes how the HTTP client used to talk to the vStack API is built.
+type TransportConfig struct {
+	ConnectTimeout time.Duration // Maximum time to establish a TCP connection and complete the TLS handshake.
+	ReadTimeout    time.Duration // Maximum time of a single request, including reading the response body.
+
+	CACertFile         string // Path to a PEM file with CA certificates trusted in addition to the system ones.
+	CACertPEM          string // PEM-encoded CA certificates trusted in addition to the system ones.
+	ClientCert         string // PEM-encoded client certificate for mutual TLS, or a path to a file containing it.
+	ClientKey          string // PEM-encoded private key of ClientCert, or a path to a file containing it.
+	InsecureSkipVerify bool   // Disables verification of the server certificate. Only for lab clusters.
+	ProxyURL           string // URL of the HTTP proxy; if empty, HTTP_PROXY/HTTPS_PROXY/NO_PROXY are used.
+}
+
+// NewHTTPClient builds an HTTP client for the vStack API from the transport configuration.
+// Zero timeouts are replaced with the defaults.
+func NewHTTPClient(cfg TransportConfig) (*http.Client, error) {
+	if cfg.ConnectTimeout <= 0 {
+		cfg.ConnectTimeout = DefaultConnectTimeout
+	}
+	if cfg.ReadTimeout <= 0 {
+		cfg.ReadTimeout = DefaultReadTimeout
+	}
+
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("NewHTTPClient: unexpected type of http.DefaultTransport: %T", http.DefaultTransport)
+	}
+
+	transport := defaultTransport.Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   cfg.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = cfg.ConnectTimeout
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("NewHTTPClient: %w", err)
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("NewHTTPClient: invalid proxy URL: %w", err)
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("NewHTTPClient: invalid proxy URL %q: scheme and host are required", cfg.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   cfg.ReadTimeout,
+	}, nil
+}
+
+// newTLSConfig builds the TLS configuration of the transport from the CA, client certificate
+// and verification settings.
+func newTLSConfig(cfg TransportConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // Explicitly requested for lab clusters.
+	}
+
+	if cfg.CACertFile != "" || cfg.CACertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if cfg.CACertFile != "" {
+			caPEM, err := os.ReadFile(cfg.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA certificate file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(caPEM) {
+				return nil, fmt.Errorf("no PEM certificates found in CA certificate file %q", cfg.CACertFile)
+			}
+		}
+		if cfg.CACertPEM != "" && !pool.AppendCertsFromPEM([]byte(cfg.CACertPEM)) {
+			return nil, fmt.Errorf("no PEM certificates found in CA certificate PEM")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, fmt.Errorf("both a client certificate and a client key are required for mutual TLS")
+		}
+		certPEM, err := readPEM(cfg.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client certificate: %w", err)
+		}
+		keyPEM, err := readPEM(cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client key: %w", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// readPEM returns value itself if it is PEM-encoded, otherwise the contents of the file it points to.
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// certificatePEM returns the PEM encoding of the TLS certificate of the test server.
+func certificatePEM(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+// newClientKeyPair generates a self-signed client certificate and returns it and its key in PEM encoding.
+func newClientKeyPair(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func newTLSTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewHTTPClientRejectsUnknownCA(t *testing.T) {
+	server := newTLSTestServer(t)
+
+	client, err := NewHTTPClient(TransportConfig{})
+	if err != nil {
+		t.Fatalf("NewHTTPClient: %v", err)
+	}
+	if resp, err := client.Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Fatal("expected a certificate verification error")
+	}
+}
+
+func TestNewHTTPClientTrustsCACert(t *testing.T) {
+	server := newTLSTestServer(t)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(certificatePEM(server)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, cfg := range map[string]TransportConfig{
+		"file":     {CACertFile: caFile},
+		"pem":      {CACertPEM: certificatePEM(server)},
+		"insecure": {InsecureSkipVerify: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			client, err := NewHTTPClient(cfg)
+			if err != nil {
+				t.Fatalf("NewHTTPClient: %v", err)
+			}
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestNewHTTPClientPresentsClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	certPEM, keyPEM := newClientKeyPair(t)
+	keyFile := filepath.Join(t.TempDir(), "client.key")
+	if err := os.WriteFile(keyFile, []byte(keyPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewHTTPClient(TransportConfig{
+		CACertPEM:  certificatePEM(server),
+		ClientCert: certPEM,
+		ClientKey:  keyFile,
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPClient: %v", err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewHTTPClientValidatesSettings(t *testing.T) {
+	for name, cfg := range map[string]TransportConfig{
+		"invalid CA PEM":     {CACertPEM: "not a certificate"},
+		"missing CA file":    {CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"client cert alone":  {ClientCert: "-----BEGIN CERTIFICATE-----"},
+		"relative proxy URL": {ProxyURL: "proxy.example.com"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewHTTPClient(cfg); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
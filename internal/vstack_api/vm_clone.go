@@ -0,0 +1,53 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"context"
+	"fmt"
+)
+
+// VmsCloneParams represents the parameters of the "vms-clone" method.
+// The clone copies the disks and NICs of the source VM, or of the VM of the source snapshot.
+// Settings left zero are inherited from the source VM.
+type VmsCloneParams struct {
+	SourceID       int64        `json:"source_id"`                 // ID of the VM to clone, or of the VM the snapshot belongs to.
+	SourceDataset  string       `json:"source_dataset,omitempty"`  // Dataset of the source snapshot.
+	SourceSnapshot string       `json:"source_snapshot,omitempty"` // Clones the snapshot instead of the current state of the VM if set.
+	Linked         bool         `json:"linked"`                    // Linked clones share the unchanged data with the source.
+	Name           string       `json:"name"`
+	Description    string       `json:"description"`
+	CPUs           int64        `json:"cpus,omitempty"`
+	RAM            int64        `json:"ram,omitempty"` // Amount of RAM in bytes.
+	CpuPriority    int64        `json:"cpu_priority,omitempty"`
+	BootMedia      int64        `json:"boot_media,omitempty"`
+	VcpuClass      int64        `json:"vcpu_class,omitempty"`
+	VdcID          int64        `json:"vdc_id"`
+	PoolSelector   string       `json:"pool_selector,omitempty"`
+	Guest          *GuestParams `json:"guest,omitempty"`
+}
+
+// VmsClone sends a JSON-RPC "vms-clone" request and parses the result.
+// The clone is returned in the Created state, like a VM created with "vms-create".
+//
+// Parameters:
+// - params: The source VM or snapshot and the settings of the clone.
+//
+// Returns:
+// - VmCreateResult: The result containing the new VM.
+// - error: An error object if the request fails or the response code is unexpected.
+func (c *Client) VmsClone(ctx context.Context, params VmsCloneParams) (VmCreateResult, error) {
+	var result VmCreateResult
+
+	if err := c.DoRequest(ctx, "vms-clone", params, &result); err != nil {
+		return VmCreateResult{}, fmt.Errorf("VmsClone: %w", err)
+	}
+
+	// Check the response code to ensure the VM was cloned.
+	if result.Code.CodeAsInt() != 1 {
+		return result, fmt.Errorf("VmsClone: unexpected code=%s", result.Code.CodeAsString())
+	}
+
+	return result, nil
+}
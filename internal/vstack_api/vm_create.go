@@ -4,10 +4,68 @@
 package vstack_api
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 )
 
+// VmCreateParams represents the parameters of the "vms-create" method.
+type VmCreateParams struct {
+	Name         string       `json:"name"`
+	Description  string       `json:"description"`
+	CPUs         int64        `json:"cpus"`
+	RAM          int64        `json:"ram"` // Amount of RAM in bytes.
+	CpuPriority  int64        `json:"cpu_priority"`
+	BootMedia    int64        `json:"boot_media"`
+	VcpuClass    int64        `json:"vcpu_class"`
+	OsType       int64        `json:"os_type"`
+	OsProfile    string       `json:"os_profile"`
+	VdcID        int64        `json:"vdc_id"`
+	PoolSelector string       `json:"pool_selector"`
+	Disks        []DiskParams `json:"disks"`
+	Guest        *GuestParams `json:"guest,omitempty"`
+}
+
+// DiskParams describes a disk to be created together with the virtual machine.
+type DiskParams struct {
+	Size      int64  `json:"size"` // Size of the disk in bytes.
+	Slot      int64  `json:"slot"`
+	IOPSLimit int64  `json:"iops_limit"`
+	MBPSLimit int64  `json:"mbps_limit"`
+	Label     string `json:"label"`
+}
+
+// GuestParams describes the guest customization applied to a new virtual machine.
+type GuestParams struct {
+	Hostname        string                `json:"hostname,omitempty"`
+	BootCmds        []string              `json:"boot_cmds,omitempty"`
+	RunCmds         []string              `json:"run_cmds,omitempty"`
+	SSHPasswordAuth int64                 `json:"ssh_password_auth,omitempty"`
+	Resolver        *ResolverParams       `json:"resolver,omitempty"`
+	Users           map[string]UserParams `json:"users,omitempty"`
+}
+
+// IsEmpty reports whether no guest customization is set.
+func (g GuestParams) IsEmpty() bool {
+	return g.Hostname == "" &&
+		len(g.BootCmds) == 0 &&
+		len(g.RunCmds) == 0 &&
+		g.SSHPasswordAuth == 0 &&
+		g.Resolver == nil &&
+		len(g.Users) == 0
+}
+
+// ResolverParams describes the DNS resolver settings of the guest OS.
+type ResolverParams struct {
+	NameServer []string `json:"name_server,omitempty"`
+	Search     string   `json:"search,omitempty"`
+}
+
+// UserParams describes a user account created in the guest OS.
+type UserParams struct {
+	SSHAuthorizedKeys []string `json:"ssh-authorized-keys,omitempty"`
+	Password          string   `json:"password,omitempty"`
+}
+
 // VmCreateResult represents the structure for the "result" field in the response to the "vm-create" method.
 type VmCreateResult struct {
 	Code CodeUnion `json:"code"`
@@ -93,18 +151,13 @@ type SigabortCreate struct {
 	Restarts int64 `json:"restarts"`
 }
 
-// VmCreate sends a JSON-RPC "vm-create" request and parses the result.
-func VmCreate(
-	requestPayload map[string]interface{},
-	authCookie string,
-	baseURL string,
-	client *http.Client,
-) (VmCreateResult, error) {
+// VmCreate sends a JSON-RPC "vms-create" request and parses the result.
+func (c *Client) VmCreate(ctx context.Context, params VmCreateParams) (VmCreateResult, error) {
 
 	var result VmCreateResult
 
 	// Call DoRequest to send the request and parse the response into result.
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vms-create", params, &result); err != nil {
 		return VmCreateResult{}, fmt.Errorf("VmCreate: %w", err)
 	}
 
@@ -4,10 +4,15 @@
 package vstack_api
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 )
 
+// VmGetParams represents the parameters of the "vm-get" method.
+type VmGetParams struct {
+	ID int64 `json:"id"`
+}
+
 // VmGetResult represents the structure for the "result" field in the response to the "vm-get" method.
 type VmGetResult struct {
 	Code CodeUnion `json:"code"`
@@ -97,17 +102,12 @@ type Sigabort struct {
 
 // VmGet sends a JSON-RPC "vm-get" request and parses the result.
 // It returns a VmGetResult struct containing the VM details or an error if the request fails.
-func VmGet(
-	requestPayload map[string]interface{},
-	authCookie string,
-	baseURL string,
-	client *http.Client,
-) (VmGetResult, error) {
+func (c *Client) VmGet(ctx context.Context, params VmGetParams) (VmGetResult, error) {
 
 	var result VmGetResult
 
 	// Call DoRequest to send the request and parse the response into result.
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vm-get", params, &result); err != nil {
 		return VmGetResult{}, fmt.Errorf("VmGet: %w", err)
 	}
 
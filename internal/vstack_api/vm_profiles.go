@@ -4,8 +4,8 @@
 package vstack_api
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 )
 
 // VmProfilesResult describes the structure of the response for the "vm-profiles" method.
@@ -24,16 +24,12 @@ type VmProfilesResult struct {
 }
 
 // VmProfiles sends a JSON-RPC "vm-profiles" request and returns the parsed result.
-func VmProfiles(
-	requestPayload map[string]interface{},
-	authCookie string,
-	baseURL string,
-	client *http.Client,
-) (VmProfilesResult, error) {
+// The method takes no parameters.
+func (c *Client) VmProfiles(ctx context.Context) (VmProfilesResult, error) {
 	var result VmProfilesResult
 
 	// Send the request and parse the response into the result structure.
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vm-profiles", nil, &result); err != nil {
 		return VmProfilesResult{}, fmt.Errorf("VmProfiles: %w", err)
 	}
 
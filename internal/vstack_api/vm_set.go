@@ -4,10 +4,37 @@
 package vstack_api
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 )
 
+// VmSetParams represents the parameters of the "vm-set" method.
+type VmSetParams struct {
+	ID       int64         `json:"id"`
+	VmParams VmSetVmParams `json:"vm_params"`
+}
+
+// VmSetVmParams holds the VM parameters to be changed by "vm-set".
+// Only non-nil fields are sent to the API.
+type VmSetVmParams struct {
+	Name         *string `json:"name,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	CPUs         *int64  `json:"cpus,omitempty"`
+	RAM          *int64  `json:"ram,omitempty"` // Amount of RAM in bytes.
+	CpuPriority  *int64  `json:"cpu_priority,omitempty"`
+	BootMedia    *int64  `json:"boot_media,omitempty"`
+	VcpuClass    *int64  `json:"vcpu_class,omitempty"`
+	OsType       *int64  `json:"os_type,omitempty"`
+	OsProfile    *string `json:"os_profile,omitempty"`
+	VdcID        *int64  `json:"vdc_id,omitempty"`
+	PoolSelector *string `json:"pool_selector,omitempty"`
+}
+
+// IsEmpty reports whether no VM parameters are set.
+func (p VmSetVmParams) IsEmpty() bool {
+	return p == VmSetVmParams{}
+}
+
 // VmSetResult represents the structure for the "result" field in the response to the "vm-set" method.
 type VmSetResult struct {
 	Code CodeUnion `json:"code"`
@@ -20,25 +47,17 @@ type VmSetResult struct {
 // It returns a VmSetResult struct containing the response message or an error if the request fails.
 //
 // Parameters:
-// - requestPayload: The JSON-RPC request payload.
-// - authCookie: The authentication cookie for the request.
-// - baseURL: The base URL of the API endpoint.
-// - client: The HTTP client used to send the request.
+// - params: The ID of the VM and the VM parameters to be changed.
 //
 // Returns:
 // - VmSetResult: The result containing the response message.
 // - error: An error object if the request fails or the response code is unexpected.
-func VmSet(
-	requestPayload map[string]interface{},
-	authCookie string,
-	baseURL string,
-	client *http.Client,
-) (VmSetResult, error) {
+func (c *Client) VmSet(ctx context.Context, params VmSetParams) (VmSetResult, error) {
 
 	var result VmSetResult
 
 	// Use the universal DoRequest helper function to send the request and parse the response.
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vm-set", params, &result); err != nil {
 		// The error may be an HTTP, JSON, or API error (text from DoRequest).
 		return VmSetResult{}, fmt.Errorf("VmSet: %w", err)
 	}
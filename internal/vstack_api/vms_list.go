@@ -0,0 +1,57 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"context"
+	"fmt"
+)
+
+// VmsListParams represents the parameters of the "vms-list" method.
+// Zero fields are omitted and do not restrict the list.
+type VmsListParams struct {
+	VdcID int64  `json:"vdc_id,omitempty"`
+	Node  int64  `json:"node,omitempty"`
+	Pool  string `json:"pool,omitempty"`
+}
+
+// VmListItem is the summary of a VM returned by "vms-list". Use "vm-get" for the full VM details.
+type VmListItem struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Vdc         int64    `json:"vdc"`
+	Node        int64    `json:"node"`
+	Pool        string   `json:"pool"`
+	AdminStatus int64    `json:"admin_status"`
+	OperStatus  int64    `json:"oper_status"`
+	Tags        []string `json:"tags"`
+}
+
+// VmsListResult represents the structure for the "result" field in the response to the "vms-list" method.
+type VmsListResult struct {
+	Code CodeUnion    `json:"code"`
+	Data []VmListItem `json:"data"`
+}
+
+// VmsList sends a JSON-RPC "vms-list" request and returns the VMs visible to the user.
+//
+// Parameters:
+// - params: The VDC, node and pool to list the VMs of; zero values match any VM.
+//
+// Returns:
+// - VmsListResult: The result containing the summaries of the VMs.
+// - error: An error object if the request fails or the response code is unexpected.
+func (c *Client) VmsList(ctx context.Context, params VmsListParams) (VmsListResult, error) {
+	var result VmsListResult
+
+	if err := c.DoRequest(ctx, "vms-list", params, &result); err != nil {
+		return VmsListResult{}, fmt.Errorf("VmsList: %w", err)
+	}
+
+	if result.Code.CodeAsInt() != 1 {
+		return result, fmt.Errorf("VmsList: unexpected code=%s", result.Code.CodeAsString())
+	}
+
+	return result, nil
+}
@@ -0,0 +1,48 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstack_api
+
+import (
+	"context"
+	"fmt"
+)
+
+// VmsMigrateParams represents the parameters of the "vms-migrate" method.
+type VmsMigrateParams struct {
+	ID   int64 `json:"id"`
+	Node int64 `json:"node"` // ID of the node to move the VM to.
+	Live bool  `json:"live"` // Migrates a running VM without stopping it; a VM that is not running is moved offline.
+}
+
+// VmsMigrateResult represents the structure for the "result" field in the response to the "vms-migrate" method.
+type VmsMigrateResult struct {
+	Code CodeUnion `json:"code"`
+	Data struct {
+		Message string `json:"message,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// VmsMigrate sends a JSON-RPC "vms-migrate" request and parses the result.
+// The migration runs in the background; the VM is on the new node once "vm-get" reports it there.
+//
+// Parameters:
+// - params: The VM, the target node and whether to migrate the VM live.
+//
+// Returns:
+// - VmsMigrateResult: The result containing the response message.
+// - error: An error object if the request fails or the response code is unexpected.
+func (c *Client) VmsMigrate(ctx context.Context, params VmsMigrateParams) (VmsMigrateResult, error) {
+	var result VmsMigrateResult
+
+	if err := c.DoRequest(ctx, "vms-migrate", params, &result); err != nil {
+		return VmsMigrateResult{}, fmt.Errorf("VmsMigrate: %w", err)
+	}
+
+	// Check the response code to ensure the migration was started.
+	if result.Code.CodeAsInt() != 1 {
+		return result, fmt.Errorf("VmsMigrate: unexpected code=%s", result.Code.CodeAsString())
+	}
+
+	return result, nil
+}
@@ -4,10 +4,16 @@
 package vstack_api
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 )
 
+// VmRemoveParams represents the parameters of the "vms-remove" method.
+type VmRemoveParams struct {
+	ID    int64 `json:"id"`
+	VdcID int64 `json:"vdc_id"`
+}
+
 // VmRemoveResult represents the structure for the "result" field in the response to the "vm-remove" method.
 type VmRemoveResult struct {
 	Code CodeUnion `json:"code"`
@@ -16,30 +22,22 @@ type VmRemoveResult struct {
 	} `json:"data,omitempty"`
 }
 
-// VmRemove sends a JSON-RPC "vm-remove" request and parses the result.
+// VmRemove sends a JSON-RPC "vms-remove" request and parses the result.
 // It returns a VmRemoveResult struct containing the response message or an error if the request fails.
 //
 // Parameters:
-// - requestPayload: The JSON-RPC request payload.
-// - authCookie: The authentication cookie for the request.
-// - baseURL: The base URL of the API endpoint.
-// - client: The HTTP client used to send the request.
+// - params: The ID of the VM to be removed and its VDC ID.
 //
 // Returns:
 // - VmRemoveResult: The result containing the response message.
 // - error: An error object if the request fails or the response code is unexpected.
-func VmRemove(
-	requestPayload map[string]interface{},
-	authCookie string,
-	baseURL string,
-	client *http.Client,
-) (VmRemoveResult, error) {
+func (c *Client) VmRemove(ctx context.Context, params VmRemoveParams) (VmRemoveResult, error) {
 
 	var result VmRemoveResult
 
 	// Call our universal DoRequest helper function.
 	// It performs an HTTP POST, checks for errors, and parses the "result" into &result.
-	if err := DoRequest(requestPayload, authCookie, baseURL, client, &result); err != nil {
+	if err := c.DoRequest(ctx, "vms-remove", params, &result); err != nil {
 		// The error may be an HTTP, JSON, or API error (text from DoRequest).
 		return VmRemoveResult{}, fmt.Errorf("VmRemove: %w", err)
 	}
@@ -0,0 +1,161 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstacktest
+
+import (
+	"encoding/json"
+	"net"
+	"sort"
+
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// Settings of the network every new Server starts with.
+const (
+	DefaultNetworkName = "default"
+	DefaultNetworkCIDR = "10.0.0.0/8"
+)
+
+// AddNetwork adds a network to the fake and returns its ID, as if it had been created outside Terraform.
+// A zero network.ID is replaced with a new ID.
+func (s *Server) AddNetwork(network vstack_api.Network) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if network.ID == 0 {
+		network.ID = s.nextNetworkID
+		s.nextNetworkID++
+	}
+	s.networks[network.ID] = &network
+	return network.ID
+}
+
+// lookupNetwork returns the network with the given ID.
+func (s *Server) lookupNetwork(id int64) (*vstack_api.Network, *rpcError) {
+	network, ok := s.networks[id]
+	if !ok {
+		return nil, errorf(codeNotFound, "network %d not found", id)
+	}
+	return network, nil
+}
+
+// networksCreate implements "networks-create".
+func (s *Server) networksCreate(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.NetworksCreateParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if params.Type != vstack_api.NetworkTypeVLAN && params.Type != vstack_api.NetworkTypeVXLAN {
+		return nil, errorf(codeInvalidParams, "invalid network type %q", params.Type)
+	}
+	if _, _, err := net.ParseCIDR(params.CIDR); err != nil {
+		return nil, errorf(codeInvalidParams, "invalid cidr %q", params.CIDR)
+	}
+
+	// Allocate the next free tag of the network type if it is not set.
+	tag := params.Tag
+	for _, network := range s.networks {
+		if network.Type != params.Type {
+			continue
+		}
+		if params.Tag != 0 && network.Tag == params.Tag {
+			return nil, errorf(codeConflict, "%s tag %d is already used by network %d", params.Type, params.Tag, network.ID)
+		}
+		if params.Tag == 0 && network.Tag >= tag {
+			tag = network.Tag + 1
+		}
+	}
+	if tag == 0 {
+		tag = 1
+	}
+
+	network := &vstack_api.Network{
+		ID:          s.nextNetworkID,
+		Name:        params.Name,
+		Description: params.Description,
+		VdcID:       params.VdcID,
+		Type:        params.Type,
+		Tag:         tag,
+		CIDR:        params.CIDR,
+		Gateway:     params.Gateway,
+		DHCP:        params.DHCP,
+	}
+	s.nextNetworkID++
+	s.networks[network.ID] = network
+	return network, nil
+}
+
+// networkGet implements "network-get".
+func (s *Server) networkGet(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.NetworkGetParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	return s.lookupNetwork(params.ID)
+}
+
+// networksList implements "networks-list". The networks are returned in ascending order of their IDs.
+func (s *Server) networksList(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.NetworksListParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+
+	networks := []*vstack_api.Network{}
+	for _, network := range s.networks {
+		if params.VdcID == 0 || network.VdcID == params.VdcID {
+			networks = append(networks, network)
+		}
+	}
+	sort.Slice(networks, func(i, j int) bool { return networks[i].ID < networks[j].ID })
+	return networks, nil
+}
+
+// networkSet implements "network-set".
+func (s *Server) networkSet(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.NetworkSetParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	network, err := s.lookupNetwork(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := params.NetworkParams
+	if p.Name != nil {
+		network.Name = *p.Name
+	}
+	if p.Description != nil {
+		network.Description = *p.Description
+	}
+	if p.Gateway != nil {
+		network.Gateway = *p.Gateway
+	}
+	if p.DHCP != nil {
+		network.DHCP = *p.DHCP
+	}
+	return map[string]string{"message": "OK"}, nil
+}
+
+// networksRemove implements "networks-remove". Like vStack, it rejects networks with attached NICs.
+func (s *Server) networksRemove(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.NetworksRemoveParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if _, err := s.lookupNetwork(params.ID); err != nil {
+		return nil, err
+	}
+	for _, v := range s.vms {
+		for _, port := range v.ports {
+			if port.NetworkID == params.ID {
+				return nil, errorf(codeConflict, "network %d has NICs attached (VM %d)", params.ID, v.id)
+			}
+		}
+	}
+
+	delete(s.networks, params.ID)
+	return map[string]string{"message": "OK"}, nil
+}
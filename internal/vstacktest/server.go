@@ -0,0 +1,293 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+// Package vstacktest provides an in-process fake of the vStack JSON-RPC API for hermetic tests.
+//
+// The fake keeps VMs, disks, NICs, snapshots and networks in memory and implements the subset of
+// "/.api/V4/.req/" used by the provider: "auth", "vms-create", "vms-clone", "vm-get", "vms-list", "vm-set", the disk,
+// NIC and snapshot methods, the power methods ("vms-restart", "vms-stop", "vms-shutdown", "vms-suspend" and
+// "vms-resume"), "vms-migrate", "vms-remove", "vm-profiles" and the network methods.
+// Parameters are decoded with the vstack_api types, so the fake follows the wire format of the client.
+package vstacktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// Default credentials, identifiers and settings of a new Server.
+const (
+	DefaultUsername     = "terraform"
+	DefaultPassword     = "terraform"
+	DefaultVdcID        = 1
+	DefaultNetworkID    = 100
+	DefaultPoolSelector = "14061357726568775332"
+	DefaultNode         = 1
+)
+
+// JSON-RPC error codes returned by the fake.
+const (
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeNotFound       = 404
+	codeConflict       = 409
+)
+
+// rpcError is the JSON-RPC error returned for a failed call.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface for the rpcError struct.
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("Error %d: %s", e.Code, e.Message)
+}
+
+// errorf returns a JSON-RPC error with a formatted message.
+func errorf(code int, format string, args ...interface{}) *rpcError {
+	return &rpcError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// injectedError is an error returned instead of the result of the next calls of a method.
+type injectedError struct {
+	err   rpcError
+	times int
+}
+
+// handlerFunc implements an API method. It is called with the server lock held and returns the "data"
+// field of a successful result.
+type handlerFunc func(s *Server, params json.RawMessage) (interface{}, *rpcError)
+
+// Server is a stateful fake of the vStack JSON-RPC API running on an httptest.Server.
+// Its methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	Username string // The username accepted by "auth".
+	Password string // The password accepted by "auth".
+
+	// TransitionPolls is the number of "vm-get" calls that report the intermediate status
+	// (e.g. Starting) after a power action before the VM reaches the target status.
+	TransitionPolls int
+
+	// AllowHotplug permits adding and removing disks and NICs while a VM is running.
+	// When it is false, those methods fail for a running VM, like on hypervisors without hotplug support.
+	AllowHotplug bool
+
+	// IgnoreShutdown makes guests ignore ACPI shutdown requests, so "vms-shutdown" leaves running VMs running.
+	IgnoreShutdown bool
+
+	// FailStarts makes "vms-restart" leave VMs in StartFailed, as if the hypervisor had aborted them.
+	FailStarts bool
+
+	mu            sync.Mutex
+	sessions      map[string]bool
+	vms           map[int64]*vm
+	networks      map[int64]*vstack_api.Network
+	nextVmID      int64
+	nextPort      int64
+	nextNetworkID int64
+	injected      map[string]*injectedError
+	calls         map[string]int
+}
+
+// handlers maps the API methods implemented by the fake to their handlers.
+var handlers = map[string]handlerFunc{
+	"vms-create":        (*Server).vmsCreate,
+	"vms-clone":         (*Server).vmsClone,
+	"vm-get":            (*Server).vmGet,
+	"vms-list":          (*Server).vmsList,
+	"vm-set":            (*Server).vmSet,
+	"vms-restart":       (*Server).vmsRestart,
+	"vms-stop":          (*Server).vmsStop,
+	"vms-shutdown":      (*Server).vmsShutdown,
+	"vms-suspend":       (*Server).vmsSuspend,
+	"vms-resume":        (*Server).vmsResume,
+	"vms-migrate":       (*Server).vmsMigrate,
+	"vms-remove":        (*Server).vmsRemove,
+	"vms-add-disk":      (*Server).vmsAddDisk,
+	"vms-disk-resize":   (*Server).vmsDiskResize,
+	"vm-remove-disk":    (*Server).vmRemoveDisk,
+	"vm-ratelimit-disk": (*Server).vmRatelimitDisk,
+	"vm-disk-set-label": (*Server).vmDiskSetLabel,
+	"vms-add-nic":       (*Server).vmsAddNic,
+	"vm-remove-nic":     (*Server).vmRemoveNic,
+	"vm-ratelimit-nic":  (*Server).vmRatelimitNic,
+	"vm-profiles":       (*Server).vmProfiles,
+	"networks-create":   (*Server).networksCreate,
+	"network-get":       (*Server).networkGet,
+	"networks-list":     (*Server).networksList,
+	"network-set":       (*Server).networkSet,
+	"networks-remove":   (*Server).networksRemove,
+
+	"vm-snapshots-create": (*Server).vmSnapshotsCreate,
+	"vm-snapshots-list":   (*Server).vmSnapshotsList,
+	"vm-snapshots-remove": (*Server).vmSnapshotsRemove,
+}
+
+// NewServer starts a new fake vStack API with the default credentials, no VMs and the default network.
+// The caller must call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		Username: DefaultUsername,
+		Password: DefaultPassword,
+		sessions: map[string]bool{},
+		vms:      map[int64]*vm{},
+		networks: map[int64]*vstack_api.Network{
+			DefaultNetworkID: {
+				ID:    DefaultNetworkID,
+				Name:  DefaultNetworkName,
+				VdcID: DefaultVdcID,
+				Type:  vstack_api.NetworkTypeVLAN,
+				Tag:   DefaultNetworkID,
+				CIDR:  DefaultNetworkCIDR,
+			},
+		},
+		nextVmID:      1000,
+		nextPort:      1,
+		nextNetworkID: DefaultNetworkID + 1,
+		injected:      map[string]*injectedError{},
+		calls:         map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// ExpireSessions invalidates all issued session cookies, so that the next requests fail with
+// 401 Unauthorized until the client authenticates again.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions = map[string]bool{}
+}
+
+// InjectError makes the next times calls of method fail with the given JSON-RPC error
+// instead of being executed.
+func (s *Server) InjectError(method string, code int, message string, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.injected[method] = &injectedError{err: rpcError{Code: code, Message: message}, times: times}
+}
+
+// Calls returns the number of received calls of method, including failed ones.
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[method]
+}
+
+// serveHTTP handles a JSON-RPC request sent to "/.api/V4/.req/".
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/.api/V4/.req/" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		ID     string          `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeResponse(w, request.ID, nil, errorf(codeInvalidRequest, "invalid request: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls[request.Method]++
+
+	// 1. "auth" is the only method that can be called without a session.
+	if request.Method == "auth" {
+		cookie, rpcErr := s.auth(request.Params)
+		if rpcErr == nil {
+			http.SetCookie(w, &http.Cookie{Name: "APIEndpoint00", Value: cookie, Path: "/"})
+		}
+		writeResponse(w, request.ID, map[string]interface{}{"cookie": map[string]string{"APIEndpoint00": cookie}}, rpcErr)
+		return
+	}
+
+	// 2. Reject requests without a valid session like the vStack front-end does.
+	cookie := strings.TrimPrefix(r.Header.Get("X-Session-Auth"), "APIEndpoint00=")
+	if !s.sessions[cookie] {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// 3. Return an injected error, if any.
+	if injected := s.injected[request.Method]; injected != nil && injected.times > 0 {
+		injected.times--
+		rpcErr := injected.err
+		writeResponse(w, request.ID, nil, &rpcErr)
+		return
+	}
+
+	// 4. Execute the method.
+	handler, ok := handlers[request.Method]
+	if !ok {
+		writeResponse(w, request.ID, nil, errorf(codeMethodNotFound, "method %q not found", request.Method))
+		return
+	}
+	data, rpcErr := handler(s, request.Params)
+	writeResponse(w, request.ID, data, rpcErr)
+}
+
+// auth validates the credentials and issues a new session cookie.
+func (s *Server) auth(raw json.RawMessage) (string, *rpcError) {
+	var params struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := decodeParams(raw, &params); err != nil {
+		return "", err
+	}
+	if params.Username != s.Username || params.Password != s.Password {
+		return "", errorf(401, "invalid username or password")
+	}
+
+	cookie := uuid.NewString()
+	s.sessions[cookie] = true
+	return cookie, nil
+}
+
+// decodeParams decodes the parameters of a call into params.
+func decodeParams(raw json.RawMessage, params interface{}) *rpcError {
+	if err := json.Unmarshal(raw, params); err != nil {
+		return errorf(codeInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
+
+// writeResponse writes a JSON-RPC response carrying either {"code": 1, "data": data} or rpcErr.
+func writeResponse(w http.ResponseWriter, id string, data interface{}, rpcErr *rpcError) {
+	response := map[string]interface{}{
+		"id":      id,
+		"jsonrpc": "2.0",
+	}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = map[string]interface{}{"code": 1, "data": data}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
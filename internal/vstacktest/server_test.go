@@ -0,0 +1,199 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstacktest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/vstack_api"
+	"terraform-provider-vstack/internal/vstacktest"
+)
+
+// newTestClient starts a fake server and returns it with an authenticated client.
+func newTestClient(t *testing.T) (*vstacktest.Server, *vstack_api.Client) {
+	t.Helper()
+
+	server := vstacktest.NewServer()
+	t.Cleanup(server.Close)
+
+	client := vstack_api.NewClient(server.URL, server.Client())
+	client.SetRetryPolicy(vstack_api.RetryPolicy{})
+	err := client.Auth(context.Background(), vstack_api.AuthParams{
+		Username: vstacktest.DefaultUsername,
+		Password: vstacktest.DefaultPassword,
+	})
+	if err != nil {
+		t.Fatalf("Auth: %v", err)
+	}
+	return server, client
+}
+
+// createTestVM creates a VM with one disk and returns its ID.
+func createTestVM(t *testing.T, client *vstack_api.Client) int64 {
+	t.Helper()
+
+	resp, err := client.VmCreate(context.Background(), vstack_api.VmCreateParams{
+		Name:      "test-vm",
+		CPUs:      2,
+		RAM:       2 << 30,
+		OsProfile: "4001",
+		VdcID:     vstacktest.DefaultVdcID,
+		Disks:     []vstack_api.DiskParams{{Size: 10 << 30, Slot: 1}},
+	})
+	if err != nil {
+		t.Fatalf("VmCreate: %v", err)
+	}
+	return resp.Data.ID
+}
+
+func TestServerVMLifecycle(t *testing.T) {
+	server, client := newTestClient(t)
+	ctx := context.Background()
+
+	id := createTestVM(t, client)
+
+	if err := helper.PerformAction(ctx, client, id, "start", 0); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if _, err := client.VmsAddNic(ctx, vstack_api.VmsAddNicParams{ID: id, NetworkID: vstacktest.DefaultNetworkID, Slot: 1}); err == nil {
+		t.Error("expected adding a NIC to a running VM to fail")
+	}
+	if err := helper.PerformAction(ctx, client, id, "stop", 0); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if _, err := client.VmsAddNic(ctx, vstack_api.VmsAddNicParams{ID: id, NetworkID: vstacktest.DefaultNetworkID, Slot: 1}); err != nil {
+		t.Fatalf("VmsAddNic: %v", err)
+	}
+
+	resp, err := client.VmGet(ctx, vstack_api.VmGetParams{ID: id})
+	if err != nil {
+		t.Fatalf("VmGet: %v", err)
+	}
+	data := resp.Data
+	if data.OperStatus != helper.Status.Offline || data.AdminStatus != helper.Status.Offline {
+		t.Errorf("expected an offline VM, got admin status %d and oper status %d", data.AdminStatus, data.OperStatus)
+	}
+	if data.OsType != 1 || data.Pool != vstacktest.DefaultPoolSelector {
+		t.Errorf("unexpected os_type %d or pool %q", data.OsType, data.Pool)
+	}
+	if len(data.Disks) != 1 || data.Disks[0].SectorSize == nil || data.Disks[0].SectorSize.Physical != 4096 {
+		t.Errorf("unexpected disks: %+v", data.Disks)
+	}
+	if len(data.NetworkPorts) != 1 {
+		t.Fatalf("expected 1 network port, got %+v", data.NetworkPorts)
+	}
+	if port := data.NetworkPorts[0]; port.Address == "" || port.MAC == "" || port.RatelimitMBits == nil {
+		t.Errorf("expected an assigned address, a MAC and a rate limit, got %+v", port)
+	}
+
+	if _, err := client.VmRemove(ctx, vstack_api.VmRemoveParams{ID: id, VdcID: vstacktest.DefaultVdcID}); err != nil {
+		t.Fatalf("VmRemove: %v", err)
+	}
+	_, err = client.VmGet(ctx, vstack_api.VmGetParams{ID: id})
+	var apiErr *vstack_api.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != 404 {
+		t.Errorf("expected a 404 error for a removed VM, got %v", err)
+	}
+	if ids := server.VmIDs(); len(ids) != 0 {
+		t.Errorf("expected no VMs, got %v", ids)
+	}
+}
+
+func TestServerClonesVM(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+
+	sourceID := createTestVM(t, client)
+	if _, err := client.VmsAddNic(ctx, vstack_api.VmsAddNicParams{ID: sourceID, NetworkID: vstacktest.DefaultNetworkID, Slot: 1}); err != nil {
+		t.Fatalf("VmsAddNic: %v", err)
+	}
+	source, err := client.VmGet(ctx, vstack_api.VmGetParams{ID: sourceID})
+	if err != nil {
+		t.Fatalf("VmGet: %v", err)
+	}
+
+	// Settings left zero are inherited from the source; the disks and NICs are copied.
+	clone, err := client.VmsClone(ctx, vstack_api.VmsCloneParams{
+		SourceID: sourceID,
+		Linked:   true,
+		Name:     "test-clone",
+		CPUs:     4,
+		VdcID:    vstacktest.DefaultVdcID,
+	})
+	if err != nil {
+		t.Fatalf("VmsClone: %v", err)
+	}
+	data := clone.Data
+	if data.ID == sourceID || data.OperStatus != helper.Status.Created {
+		t.Errorf("expected a new Created VM, got ID %d and oper status %d", data.ID, data.OperStatus)
+	}
+	if data.CPUs != 4 || data.RAM != source.Data.RAM || data.OsProfile != source.Data.OsProfile {
+		t.Errorf("unexpected cpus %d, ram %d or os_profile %q", data.CPUs, data.RAM, data.OsProfile)
+	}
+	if len(data.Disks) != 1 || data.Disks[0].Size != source.Data.Disks[0].Size || data.Disks[0].GUID == source.Data.Disks[0].GUID {
+		t.Errorf("expected a copy of the source disk with a new GUID, got %+v", data.Disks)
+	}
+	if len(data.NetworkPorts) != 1 || data.NetworkPorts[0].Slot != 1 || data.NetworkPorts[0].PortID == source.Data.NetworkPorts[0].PortID {
+		t.Errorf("expected a copy of the source NIC with a new port, got %+v", data.NetworkPorts)
+	}
+
+	// A snapshot is cloned only if it exists.
+	_, err = client.VmsClone(ctx, vstack_api.VmsCloneParams{
+		SourceID:       sourceID,
+		SourceDataset:  source.Data.RootDatasetName,
+		SourceSnapshot: "missing",
+		Name:           "test-clone-2",
+		VdcID:          vstacktest.DefaultVdcID,
+	})
+	var apiErr *vstack_api.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != 404 {
+		t.Errorf("expected a 404 error for a missing snapshot, got %v", err)
+	}
+}
+
+func TestServerReportsTransitionStatus(t *testing.T) {
+	server, client := newTestClient(t)
+	ctx := context.Background()
+	server.TransitionPolls = 1
+
+	id := createTestVM(t, client)
+	if _, err := client.VmsStartStop(ctx, "vms-restart", vstack_api.VmsStartStopParams{ID: id}); err != nil {
+		t.Fatalf("VmsStartStop: %v", err)
+	}
+
+	for _, want := range []int64{helper.Status.Starting, helper.Status.Started} {
+		resp, err := client.VmGet(ctx, vstack_api.VmGetParams{ID: id})
+		if err != nil {
+			t.Fatalf("VmGet: %v", err)
+		}
+		if resp.Data.OperStatus != want {
+			t.Errorf("expected oper status %d, got %d", want, resp.Data.OperStatus)
+		}
+	}
+}
+
+func TestServerSessionsAndInjectedErrors(t *testing.T) {
+	server, client := newTestClient(t)
+	ctx := context.Background()
+
+	// An expired session is renewed transparently by the client.
+	server.ExpireSessions()
+	if _, err := client.VmProfiles(ctx); err != nil {
+		t.Fatalf("VmProfiles after session expiry: %v", err)
+	}
+	if got := server.Calls("auth"); got != 2 {
+		t.Errorf("expected the client to authenticate again, got %d auth calls", got)
+	}
+
+	server.InjectError("vm-profiles", 500, "internal error", 1)
+	if _, err := client.VmProfiles(ctx); err == nil {
+		t.Error("expected the injected error")
+	}
+	if _, err := client.VmProfiles(ctx); err != nil {
+		t.Errorf("expected the injected error to be returned once, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstacktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// ExpireSnapshot makes a snapshot of a VM expire, as if its retention had elapsed.
+// It returns false if the VM or the snapshot does not exist.
+func (s *Server) ExpireSnapshot(vmID int64, name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.vms[vmID]
+	if !ok {
+		return false
+	}
+	for i := range v.snapshots {
+		if v.snapshots[i].Name == name {
+			v.snapshots[i].Expires = time.Now().Unix() - 1
+			return true
+		}
+	}
+	return false
+}
+
+// pruneSnapshots removes the expired snapshots of the VM.
+func (v *vm) pruneSnapshots() {
+	now := time.Now().Unix()
+	kept := v.snapshots[:0]
+	for _, snapshot := range v.snapshots {
+		if snapshot.Expires == 0 || snapshot.Expires > now {
+			kept = append(kept, snapshot)
+		}
+	}
+	v.snapshots = kept
+}
+
+// vmSnapshotsCreate implements "vm-snapshots-create". Only the root dataset of a VM can be snapshotted.
+func (s *Server) vmSnapshotsCreate(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmSnapshotsCreateParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if params.Dataset != v.rootDataset() {
+		return nil, errorf(codeNotFound, "dataset %q of VM %d not found", params.Dataset, v.id)
+	}
+	if params.Retention < 0 {
+		return nil, errorf(codeInvalidParams, "invalid retention %d", params.Retention)
+	}
+	v.pruneSnapshots()
+
+	now := time.Now().Unix()
+	name := params.Name
+	if name == "" {
+		name = fmt.Sprintf("snap-%d-%d", now, len(v.snapshots)+1)
+	}
+	for _, snapshot := range v.snapshots {
+		if snapshot.Dataset == params.Dataset && snapshot.Name == name {
+			return nil, errorf(codeConflict, "snapshot %s@%s already exists", params.Dataset, name)
+		}
+	}
+
+	snapshot := vstack_api.VmSnapshot{
+		Name:        name,
+		VmID:        v.id,
+		Dataset:     params.Dataset,
+		Description: params.Description,
+		Created:     now,
+		Used:        1 << 20,
+	}
+	if params.Retention > 0 {
+		snapshot.Expires = now + params.Retention
+	}
+	v.snapshots = append(v.snapshots, snapshot)
+	return snapshot, nil
+}
+
+// vmSnapshotsList implements "vm-snapshots-list".
+func (s *Server) vmSnapshotsList(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmSnapshotsListParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	v.pruneSnapshots()
+
+	snapshots := []vstack_api.VmSnapshot{}
+	for _, snapshot := range v.snapshots {
+		if params.Dataset == "" || snapshot.Dataset == params.Dataset {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+// vmSnapshotsRemove implements "vm-snapshots-remove".
+func (s *Server) vmSnapshotsRemove(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmSnapshotsRemoveParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	v.pruneSnapshots()
+
+	for i, snapshot := range v.snapshots {
+		if snapshot.Dataset == params.Dataset && snapshot.Name == params.Name {
+			v.snapshots = append(v.snapshots[:i], v.snapshots[i+1:]...)
+			return map[string]string{"message": "OK"}, nil
+		}
+	}
+	return nil, errorf(codeNotFound, "snapshot %s@%s not found", params.Dataset, params.Name)
+}
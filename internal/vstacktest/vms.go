@@ -0,0 +1,921 @@
+// Copyright (c) Ivan Brykalov, ivbrykalov@gmail.com
+// SPDX-License-Identifier: MIT
+
+package vstacktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"terraform-provider-vstack/internal/helper"
+	"terraform-provider-vstack/internal/vstack_api"
+)
+
+// Default sector sizes of a disk created without explicit sizes.
+const (
+	defaultLogicalSectorSize  = 512
+	defaultPhysicalSectorSize = 4096
+)
+
+// osProfile is an OS profile returned by "vm-profiles".
+type osProfile struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MinSize     int64  `json:"min_size"`
+}
+
+// osTypeProfiles is an OS type with its profiles returned by "vm-profiles".
+type osTypeProfiles struct {
+	ID       int64       `json:"id"`
+	Name     string      `json:"name"`
+	Profiles []osProfile `json:"profiles"`
+}
+
+// profiles is the static catalogue of OS types and profiles served by the fake.
+var profiles = map[string]osTypeProfiles{
+	"1": {ID: 1, Name: "Linux", Profiles: []osProfile{
+		{ID: 4001, Name: "Ubuntu", Description: "Ubuntu Linux (64-bit)", MinSize: 10737418240},
+		{ID: 4002, Name: "Debian", Description: "Debian GNU/Linux (64-bit)", MinSize: 10737418240},
+	}},
+	"2": {ID: 2, Name: "Windows", Profiles: []osProfile{
+		{ID: 5001, Name: "Windows Server 2022", Description: "Microsoft Windows Server 2022", MinSize: 53687091200},
+	}},
+}
+
+// vm is the state of a virtual machine kept by the fake.
+type vm struct {
+	id          int64
+	name        string
+	description string
+	cpus        int64
+	ram         int64 // Amount of RAM in bytes.
+	cpuPriority int64
+	bootMedia   int64
+	vcpuClass   int64
+	osType      int64
+	osProfile   string
+	vdc         int64
+	pool        string
+	node        int64
+	created     int64
+	modified    int64
+
+	adminStatus int64
+	operStatus  int64
+
+	// pendingPolls is the number of "vm-get" calls that still report the intermediate operStatus
+	// of a power transition before the VM reaches adminStatus.
+	pendingPolls int
+
+	// migrationNode is the node of a pending migration, reached after migrationPolls more "vm-get" calls.
+	migrationNode  int64
+	migrationPolls int
+
+	// hvFaults are the hypervisor faults reported by "vm-get".
+	hvFaults vstack_api.HVFaults
+
+	disks     []vstack_api.Disk
+	ports     []vstack_api.NetworkPort
+	tags      []string
+	snapshots []vstack_api.VmSnapshot
+}
+
+// rootDataset returns the name of the root dataset of the VM.
+func (v *vm) rootDataset() string {
+	return fmt.Sprintf("%s/vms/%d", v.pool, v.id)
+}
+
+// running reports whether the VM is started or is being started. A VM in a failure status is not running.
+func (v *vm) running() bool {
+	return v.adminStatus == helper.Status.Started && !helper.IsFailedStatus(v.operStatus)
+}
+
+// setStatus starts a power transition to target, reporting transition for polls "vm-get" calls first.
+func (v *vm) setStatus(target, transition int64, polls int) {
+	v.adminStatus = target
+	v.modified = time.Now().Unix()
+	if polls > 0 {
+		v.operStatus = transition
+		v.pendingPolls = polls
+		return
+	}
+	v.operStatus = target
+	v.pendingPolls = 0
+}
+
+// poll advances a pending power transition or migration after a "vm-get" call has reported it.
+func (v *vm) poll() {
+	if v.migrationPolls > 0 {
+		v.migrationPolls--
+		if v.migrationPolls == 0 {
+			v.node = v.migrationNode
+		}
+	}
+	if v.pendingPolls == 0 {
+		return
+	}
+	v.pendingPolls--
+	if v.pendingPolls == 0 {
+		v.operStatus = v.adminStatus
+	}
+}
+
+// disk returns the disk with the given GUID.
+func (v *vm) disk(guid string) (*vstack_api.Disk, *rpcError) {
+	for i := range v.disks {
+		if v.disks[i].GUID == guid {
+			return &v.disks[i], nil
+		}
+	}
+	return nil, errorf(codeNotFound, "disk %s not found in VM %d", guid, v.id)
+}
+
+// port returns the network port with the given ID.
+func (v *vm) port(portID int64) (*vstack_api.NetworkPort, *rpcError) {
+	for i := range v.ports {
+		if v.ports[i].PortID == portID {
+			return &v.ports[i], nil
+		}
+	}
+	return nil, errorf(codeNotFound, "network port %d not found in VM %d", portID, v.id)
+}
+
+// checkDiskSlot fails if another disk of the VM occupies the slot.
+func (v *vm) checkDiskSlot(slot int64) *rpcError {
+	for _, d := range v.disks {
+		if d.Slot == slot {
+			return errorf(codeConflict, "disk slot %d of VM %d is already in use", slot, v.id)
+		}
+	}
+	return nil
+}
+
+// Status returns the administrative and operational statuses of a VM and whether it exists.
+func (s *Server) Status(vmID int64) (int64, int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.vms[vmID]
+	if !ok {
+		return 0, 0, false
+	}
+	return v.adminStatus, v.operStatus, true
+}
+
+// FailVM puts a VM into a failure status, e.g. StartFailed, as if the hypervisor had aborted it restarts times.
+// It returns false if the VM does not exist.
+func (s *Server) FailVM(vmID, status, restarts int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.vms[vmID]
+	if !ok {
+		return false
+	}
+	v.fail(status, restarts)
+	return true
+}
+
+// fail puts the VM into a failure status with the hypervisor faults of restarts aborts.
+func (v *vm) fail(status, restarts int64) {
+	v.operStatus = status
+	v.pendingPolls = 0
+	v.modified = time.Now().Unix()
+	v.hvFaults = vstack_api.HVFaults{Sigabort: vstack_api.Sigabort{Interval: 60, Restarts: restarts}}
+}
+
+// VmIDs returns the identifiers of the existing VMs in ascending order.
+func (s *Server) VmIDs() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, 0, len(s.vms))
+	for id := range s.vms {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// SetTags replaces the tags of a VM reported by "vms-list". It returns false if the VM does not exist.
+func (s *Server) SetTags(vmID int64, tags ...string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.vms[vmID]
+	if !ok {
+		return false
+	}
+	v.tags = append([]string{}, tags...)
+	return true
+}
+
+// lookupVM returns the VM with the given ID.
+func (s *Server) lookupVM(id int64) (*vm, *rpcError) {
+	v, ok := s.vms[id]
+	if !ok {
+		return nil, errorf(codeNotFound, "VM %d not found", id)
+	}
+	return v, nil
+}
+
+// checkHotplug fails if the VM is running and hotplug is not allowed.
+func (s *Server) checkHotplug(v *vm) *rpcError {
+	if v.running() && !s.AllowHotplug {
+		return errorf(codeConflict, "VM %d must be stopped", v.id)
+	}
+	return nil
+}
+
+// newDisk returns a disk with a new GUID and the default sector sizes if they are not set.
+func newDisk(size, slot, iopsLimit, mbpsLimit int64, label string, sectorSize vstack_api.SectorSizeParams) vstack_api.Disk {
+	if sectorSize.Logical == 0 {
+		sectorSize.Logical = defaultLogicalSectorSize
+	}
+	if sectorSize.Physical == 0 {
+		sectorSize.Physical = defaultPhysicalSectorSize
+	}
+	return vstack_api.Disk{
+		GUID:       uuid.NewString(),
+		Size:       size,
+		Slot:       slot,
+		IOPSLimit:  &iopsLimit,
+		MBPSLimit:  &mbpsLimit,
+		Label:      label,
+		SectorSize: &vstack_api.SectorSize{Logical: sectorSize.Logical, Physical: sectorSize.Physical},
+	}
+}
+
+// vmData returns the "data" of the "vm-get" result for a VM.
+func vmData(v *vm) interface{} {
+	var result vstack_api.VmGetResult
+	data := &result.Data
+
+	data.ID = v.id
+	data.Name = v.name
+	if v.description != "" {
+		description := v.description
+		data.Description = &description
+	}
+	data.CPUs = v.cpus
+	data.RAM = v.ram
+	data.CpuPriority = v.cpuPriority
+	data.BootMediaID = v.bootMedia
+	data.VcpuClass = v.vcpuClass
+	data.OsType = v.osType
+	data.OsProfile = v.osProfile
+	data.Vdc = v.vdc
+	data.Pool = v.pool
+	data.Node = v.node
+	data.Created = v.created
+	data.Modified = v.modified
+	data.CreateCompleted = 1
+	data.HwVersion = 1
+	data.AdminStatus = v.adminStatus
+	data.Status = v.operStatus
+	data.OperStatus = v.operStatus
+	data.HVFaults = v.hvFaults
+	data.OperStatusTS = v.modified
+	data.UEFI = "OVMF_CODE.fd"
+	data.RootDatasetName = v.rootDataset()
+	data.RootDataset = data.RootDatasetName
+	data.Disks = append([]vstack_api.Disk{}, v.disks...)
+	data.NetworkPorts = append([]vstack_api.NetworkPort{}, v.ports...)
+	data.Guest = &vstack_api.Guest{
+		RamUsed:            v.ram / 2,
+		RamBallonPerformed: v.ram / 4,
+		RamBallonRequested: v.ram / 4,
+	}
+	return data
+}
+
+// vmsCreate implements "vms-create". The new VM is Created and has to be started with "vms-restart".
+func (s *Server) vmsCreate(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmCreateParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if params.Name == "" {
+		return nil, errorf(codeInvalidParams, "name is required")
+	}
+	if params.CPUs <= 0 || params.RAM <= 0 {
+		return nil, errorf(codeInvalidParams, "cpus and ram must be positive")
+	}
+
+	osType, rpcErr := profileOsType(params.OsProfile)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if params.OsType != 0 {
+		osType = params.OsType
+	}
+
+	now := time.Now().Unix()
+	v := &vm{
+		id:          s.nextVmID,
+		name:        params.Name,
+		description: params.Description,
+		cpus:        params.CPUs,
+		ram:         params.RAM,
+		cpuPriority: params.CpuPriority,
+		bootMedia:   params.BootMedia,
+		vcpuClass:   params.VcpuClass,
+		osType:      osType,
+		osProfile:   params.OsProfile,
+		vdc:         params.VdcID,
+		pool:        params.PoolSelector,
+		node:        DefaultNode,
+		created:     now,
+		modified:    now,
+		adminStatus: helper.Status.Created,
+		operStatus:  helper.Status.Created,
+	}
+	if v.pool == "" {
+		v.pool = DefaultPoolSelector
+	}
+	for _, d := range params.Disks {
+		if err := v.checkDiskSlot(d.Slot); err != nil {
+			return nil, err
+		}
+		v.disks = append(v.disks, newDisk(d.Size, d.Slot, d.IOPSLimit, d.MBPSLimit, d.Label, vstack_api.SectorSizeParams{}))
+	}
+
+	s.nextVmID++
+	s.vms[v.id] = v
+	return vmData(v), nil
+}
+
+// vmsClone implements "vms-clone". The clone is Created with copies of the disks and NICs of the source VM;
+// the fake does not keep the content of snapshots, so cloning a snapshot copies the current disks of its VM.
+func (s *Server) vmsClone(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmsCloneParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if params.Name == "" {
+		return nil, errorf(codeInvalidParams, "name is required")
+	}
+	if params.CPUs < 0 || params.RAM < 0 {
+		return nil, errorf(codeInvalidParams, "cpus and ram must not be negative")
+	}
+	source, err := s.lookupVM(params.SourceID)
+	if err != nil {
+		return nil, err
+	}
+	if params.SourceSnapshot != "" {
+		source.pruneSnapshots()
+		found := false
+		for _, snapshot := range source.snapshots {
+			if snapshot.Dataset == params.SourceDataset && snapshot.Name == params.SourceSnapshot {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errorf(codeNotFound, "snapshot %s@%s not found", params.SourceDataset, params.SourceSnapshot)
+		}
+	}
+
+	now := time.Now().Unix()
+	v := &vm{
+		id:          s.nextVmID,
+		name:        params.Name,
+		description: params.Description,
+		cpus:        source.cpus,
+		ram:         source.ram,
+		cpuPriority: source.cpuPriority,
+		bootMedia:   source.bootMedia,
+		vcpuClass:   source.vcpuClass,
+		osType:      source.osType,
+		osProfile:   source.osProfile,
+		vdc:         params.VdcID,
+		pool:        source.pool,
+		node:        DefaultNode,
+		created:     now,
+		modified:    now,
+		adminStatus: helper.Status.Created,
+		operStatus:  helper.Status.Created,
+	}
+	if params.CPUs != 0 {
+		v.cpus = params.CPUs
+	}
+	if params.RAM != 0 {
+		v.ram = params.RAM
+	}
+	if params.CpuPriority != 0 {
+		v.cpuPriority = params.CpuPriority
+	}
+	if params.BootMedia != 0 {
+		v.bootMedia = params.BootMedia
+	}
+	if params.VcpuClass != 0 {
+		v.vcpuClass = params.VcpuClass
+	}
+	if params.PoolSelector != "" {
+		v.pool = params.PoolSelector
+	}
+	for _, d := range source.disks {
+		var iopsLimit, mbpsLimit int64
+		if d.IOPSLimit != nil {
+			iopsLimit = *d.IOPSLimit
+		}
+		if d.MBPSLimit != nil {
+			mbpsLimit = *d.MBPSLimit
+		}
+		var sectorSize vstack_api.SectorSizeParams
+		if d.SectorSize != nil {
+			sectorSize = vstack_api.SectorSizeParams{Logical: d.SectorSize.Logical, Physical: d.SectorSize.Physical}
+		}
+		v.disks = append(v.disks, newDisk(d.Size, d.Slot, iopsLimit, mbpsLimit, d.Label, sectorSize))
+	}
+	for _, p := range source.ports {
+		portID := s.nextPort
+		s.nextPort++
+
+		// The copied NICs get new ports, MAC and IP addresses on the networks of the source.
+		v.ports = append(v.ports, vstack_api.NetworkPort{
+			Address:        fmt.Sprintf("10.%d.%d.%d", (portID>>16)&0xff, (portID>>8)&0xff, portID&0xff),
+			IPGuard:        p.IPGuard,
+			MAC:            fmt.Sprintf("52:54:00:%02x:%02x:%02x", (portID>>16)&0xff, (portID>>8)&0xff, portID&0xff),
+			NetworkID:      p.NetworkID,
+			PortID:         portID,
+			RatelimitMBits: new(int64),
+			Slot:           p.Slot,
+		})
+		if p.RatelimitMBits != nil {
+			*v.ports[len(v.ports)-1].RatelimitMBits = *p.RatelimitMBits
+		}
+	}
+
+	s.nextVmID++
+	s.vms[v.id] = v
+	return vmData(v), nil
+}
+
+// profileOsType returns the OS type of an OS profile.
+func profileOsType(profile string) (int64, *rpcError) {
+	for _, osType := range profiles {
+		for _, p := range osType.Profiles {
+			if fmt.Sprint(p.ID) == profile {
+				return osType.ID, nil
+			}
+		}
+	}
+	return 0, errorf(codeInvalidParams, "unknown os_profile %q", profile)
+}
+
+// vmGet implements "vm-get".
+func (s *Server) vmGet(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmGetParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := vmData(v)
+	v.poll()
+	return data, nil
+}
+
+// vmsList implements "vms-list". The VMs are returned in ascending order of their IDs.
+func (s *Server) vmsList(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmsListParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+
+	items := []vstack_api.VmListItem{}
+	for _, v := range s.vms {
+		if (params.VdcID != 0 && v.vdc != params.VdcID) || (params.Node != 0 && v.node != params.Node) ||
+			(params.Pool != "" && v.pool != params.Pool) {
+			continue
+		}
+		items = append(items, vstack_api.VmListItem{
+			ID:          v.id,
+			Name:        v.name,
+			Vdc:         v.vdc,
+			Node:        v.node,
+			Pool:        v.pool,
+			AdminStatus: v.adminStatus,
+			OperStatus:  v.operStatus,
+			Tags:        append([]string{}, v.tags...),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+// vmSet implements "vm-set".
+func (s *Server) vmSet(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmSetParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := params.VmParams
+	if v.running() && (p.BootMedia != nil || p.VcpuClass != nil) {
+		return nil, errorf(codeConflict, "VM %d must be stopped to change its boot media or vCPU class", v.id)
+	}
+	if p.OsProfile != nil {
+		osType, err := profileOsType(*p.OsProfile)
+		if err != nil {
+			return nil, err
+		}
+		v.osProfile = *p.OsProfile
+		v.osType = osType
+	}
+	if p.Name != nil {
+		v.name = *p.Name
+	}
+	if p.Description != nil {
+		v.description = *p.Description
+	}
+	if p.CPUs != nil {
+		v.cpus = *p.CPUs
+	}
+	if p.RAM != nil {
+		v.ram = *p.RAM
+	}
+	if p.CpuPriority != nil {
+		v.cpuPriority = *p.CpuPriority
+	}
+	if p.BootMedia != nil {
+		v.bootMedia = *p.BootMedia
+	}
+	if p.VcpuClass != nil {
+		v.vcpuClass = *p.VcpuClass
+	}
+	if p.OsType != nil {
+		v.osType = *p.OsType
+	}
+	if p.VdcID != nil {
+		v.vdc = *p.VdcID
+	}
+	if p.PoolSelector != nil {
+		v.pool = *p.PoolSelector
+	}
+	v.modified = time.Now().Unix()
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmsRestart implements "vms-restart", which starts a stopped VM.
+func (s *Server) vmsRestart(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmsStartStopParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	v.setStatus(helper.Status.Started, helper.Status.Starting, s.TransitionPolls)
+	if s.FailStarts {
+		v.fail(helper.Status.StartFailed, 3)
+	}
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmsMigrate implements "vms-migrate". A running VM can only be migrated live, a VM that is not running only offline.
+// The VM is reported on the new node after TransitionPolls "vm-get" calls.
+func (s *Server) vmsMigrate(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmsMigrateParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case params.Node <= 0:
+		return nil, errorf(codeInvalidParams, "invalid node %d", params.Node)
+	case v.migrationPolls > 0:
+		return nil, errorf(codeConflict, "VM %d is already being migrated", v.id)
+	case params.Live && v.operStatus != helper.Status.Started:
+		return nil, errorf(codeConflict, "VM %d must be running to be migrated live", v.id)
+	case !params.Live && v.running():
+		return nil, errorf(codeConflict, "VM %d must be stopped to be migrated offline", v.id)
+	}
+
+	v.modified = time.Now().Unix()
+	if s.TransitionPolls > 0 {
+		v.migrationNode = params.Node
+		v.migrationPolls = s.TransitionPolls
+	} else {
+		v.node = params.Node
+	}
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmsStop implements "vms-stop".
+func (s *Server) vmsStop(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmsStartStopParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	v.setStatus(helper.Status.Offline, helper.Status.Stopping, s.TransitionPolls)
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmsShutdown implements "vms-shutdown". The guest of a running VM shuts it down unless IgnoreShutdown is set.
+func (s *Server) vmsShutdown(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmsStartStopParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if v.operStatus != helper.Status.Started {
+		return nil, errorf(codeConflict, "VM %d must be running to be shut down", v.id)
+	}
+
+	if !s.IgnoreShutdown {
+		v.setStatus(helper.Status.Offline, helper.Status.Stopping, s.TransitionPolls)
+	}
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmsSuspend implements "vms-suspend". Only running VMs can be suspended.
+func (s *Server) vmsSuspend(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmsStartStopParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if v.operStatus != helper.Status.Started {
+		return nil, errorf(codeConflict, "VM %d must be running to be suspended", v.id)
+	}
+
+	v.setStatus(helper.Status.Suspended, helper.Status.Suspending, s.TransitionPolls)
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmsResume implements "vms-resume". Only suspended VMs can be resumed.
+func (s *Server) vmsResume(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmsStartStopParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if v.operStatus != helper.Status.Suspended {
+		return nil, errorf(codeConflict, "VM %d must be suspended to be resumed", v.id)
+	}
+
+	v.setStatus(helper.Status.Started, helper.Status.Resuming, s.TransitionPolls)
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmsRemove implements "vms-remove". Only stopped VMs can be removed.
+func (s *Server) vmsRemove(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmRemoveParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if v.running() || v.adminStatus == helper.Status.Suspended {
+		return nil, errorf(codeConflict, "VM %d must be stopped", v.id)
+	}
+
+	delete(s.vms, v.id)
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmsAddDisk implements "vms-add-disk".
+func (s *Server) vmsAddDisk(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmsAddDiskParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.VmID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkHotplug(v); err != nil {
+		return nil, err
+	}
+	if err := v.checkDiskSlot(params.Slot); err != nil {
+		return nil, err
+	}
+
+	d := newDisk(params.Size, params.Slot, params.IOPSLimit, params.MBPSLimit, params.Label, params.SectorSize)
+	v.disks = append(v.disks, d)
+	return map[string]interface{}{
+		"guid":        d.GUID,
+		"sector_size": d.SectorSize,
+		"size":        d.Size,
+		"label":       d.Label,
+		"slot":        d.Slot,
+	}, nil
+}
+
+// vmsDiskResize implements "vms-disk-resize". Disks can only grow.
+func (s *Server) vmsDiskResize(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmsDiskResizeParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	d, err := v.disk(params.DiskGUID)
+	if err != nil {
+		return nil, err
+	}
+	if params.Size < d.Size {
+		return nil, errorf(codeInvalidParams, "disk %s cannot be shrunk from %d to %d bytes", d.GUID, d.Size, params.Size)
+	}
+
+	d.Size = params.Size
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmRemoveDisk implements "vm-remove-disk".
+func (s *Server) vmRemoveDisk(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmRemoveDiskParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.VmID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkHotplug(v); err != nil {
+		return nil, err
+	}
+	if _, err := v.disk(params.DiskGUID); err != nil {
+		return nil, err
+	}
+
+	for i := range v.disks {
+		if v.disks[i].GUID == params.DiskGUID {
+			v.disks = append(v.disks[:i], v.disks[i+1:]...)
+			break
+		}
+	}
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmRatelimitDisk implements "vm-ratelimit-disk".
+func (s *Server) vmRatelimitDisk(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmRatelimitDiskParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.VmID)
+	if err != nil {
+		return nil, err
+	}
+	d, err := v.disk(params.DiskGUID)
+	if err != nil {
+		return nil, err
+	}
+
+	iopsLimit, mbpsLimit := params.IOPSLimit, params.MBPSLimit
+	d.IOPSLimit, d.MBPSLimit = &iopsLimit, &mbpsLimit
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmDiskSetLabel implements "vm-disk-set-label".
+func (s *Server) vmDiskSetLabel(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmDiskSetLabelParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.VmID)
+	if err != nil {
+		return nil, err
+	}
+	d, err := v.disk(params.GUID)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Label = params.Label
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmsAddNic implements "vms-add-nic". A port without an address gets one assigned by the fake.
+func (s *Server) vmsAddNic(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmsAddNicParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkHotplug(v); err != nil {
+		return nil, err
+	}
+	for _, p := range v.ports {
+		if p.Slot == params.Slot {
+			return nil, errorf(codeConflict, "NIC slot %d of VM %d is already in use", params.Slot, v.id)
+		}
+	}
+
+	portID := s.nextPort
+	s.nextPort++
+
+	port := vstack_api.NetworkPort{
+		Address:        params.Address,
+		IPGuard:        params.IPGuard,
+		MAC:            fmt.Sprintf("52:54:00:%02x:%02x:%02x", (portID>>16)&0xff, (portID>>8)&0xff, portID&0xff),
+		NetworkID:      params.NetworkID,
+		PortID:         portID,
+		RatelimitMBits: new(int64),
+		Slot:           params.Slot,
+	}
+	if params.MAC != "" {
+		port.MAC = params.MAC
+	}
+	if port.Address == "" {
+		port.Address = fmt.Sprintf("10.%d.%d.%d", (portID>>16)&0xff, (portID>>8)&0xff, portID&0xff)
+	}
+	if params.RatelimitMBits != nil {
+		*port.RatelimitMBits = *params.RatelimitMBits
+	}
+	v.ports = append(v.ports, port)
+	return port, nil
+}
+
+// vmRemoveNic implements "vm-remove-nic".
+func (s *Server) vmRemoveNic(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmRemoveNicParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.VmID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkHotplug(v); err != nil {
+		return nil, err
+	}
+	if _, err := v.port(params.PortID); err != nil {
+		return nil, err
+	}
+
+	for i := range v.ports {
+		if v.ports[i].PortID == params.PortID {
+			v.ports = append(v.ports[:i], v.ports[i+1:]...)
+			break
+		}
+	}
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmRatelimitNic implements "vm-ratelimit-nic".
+func (s *Server) vmRatelimitNic(raw json.RawMessage) (interface{}, *rpcError) {
+	var params vstack_api.VmRatelimitNicParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	v, err := s.lookupVM(params.VmID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := v.port(params.PortID)
+	if err != nil {
+		return nil, err
+	}
+
+	ratelimit := params.RatelimitMBits
+	p.RatelimitMBits = &ratelimit
+	return map[string]string{"message": "OK"}, nil
+}
+
+// vmProfiles implements "vm-profiles".
+func (s *Server) vmProfiles(json.RawMessage) (interface{}, *rpcError) {
+	return profiles, nil
+}